@@ -0,0 +1,156 @@
+package cleanenv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	setterType = reflect.TypeOf((*Setter)(nil)).Elem()
+	timeType   = reflect.TypeOf(time.Time{})
+)
+
+// Check verifies the tags of the configuration structure without reading any source.
+// It is intended to be called from unit tests to find configuration mistakes before deployment.
+//
+// Check reports:
+//   - field types that can't be read from environment variables;
+//   - env-default and env-oneof values that can't be parsed into the field type;
+//   - empty separators and separators colliding with the map key-value delimiter;
+//   - invalid env-layout values and layouts of fields other than time.Time;
+//   - invalid env-pattern regular expressions;
+//   - environment variable names used by more than one field.
+//
+// The configuration can be a structure or a pointer to a structure, it is not modified.
+// Options changing environment variable names, e.g. WithAutoEnv, are taken into account.
+//
+// Example:
+//
+//	func TestConfig(t *testing.T) {
+//		if err := cleanenv.Check(&Config{}); err != nil {
+//			t.Error(err)
+//		}
+//	}
+func Check(cfg interface{}, opts ...Option) error {
+	t := reflect.TypeOf(cfg)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("wrong type %v, structure expected", reflect.TypeOf(cfg))
+	}
+
+	// check a zero value, so defaults are parsed into empty fields
+	_, metaInfo, err := readStructTree(reflect.New(t).Interface(), newOptions(opts))
+	if err != nil {
+		return err
+	}
+
+	problems := make([]string, 0)
+	for i := range metaInfo {
+		problems = append(problems, metaInfo[i].check()...)
+	}
+	problems = append(problems, duplicateEnv(metaInfo)...)
+
+	if len(problems) != 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// check returns the descriptions of problems with the field tags
+func (sm *structMeta) check() []string {
+	path := sm.path + sm.fieldName
+	valueType := sm.fieldValue.Type()
+	problems := make([]string, 0)
+
+	// fields without environment variables and defaults are read from files only
+	if len(sm.envList) == 0 && sm.defValue == nil {
+		return problems
+	}
+
+	if !supportedType(valueType) {
+		return append(problems, fmt.Sprintf("field %q: type %s is not supported", path, valueType))
+	}
+
+	if valueType.Kind() == reflect.Slice || valueType.Kind() == reflect.Map {
+		switch {
+		case sm.separator == "":
+			problems = append(problems, fmt.Sprintf("field %q: %s is empty", path, TagEnvSeparator))
+		case valueType.Kind() == reflect.Map && strings.Contains(sm.separator, ":"):
+			problems = append(problems, fmt.Sprintf("field %q: %s %q collides with the map key-value delimiter", path, TagEnvSeparator, sm.separator))
+		}
+	}
+
+	if _, ok := sm.fieldTag.Lookup(TagEnvLayout); ok && valueType != timeType {
+		problems = append(problems, fmt.Sprintf("field %q: %s is not supported for type %s", path, TagEnvLayout, valueType))
+	}
+	if sm.layout != nil && !validLayout(*sm.layout) {
+		problems = append(problems, fmt.Sprintf("field %q: invalid %s value %q", path, TagEnvLayout, *sm.layout))
+	}
+
+	if sm.defValue != nil {
+		value := reflect.New(valueType).Elem()
+		if err := parseValue(value, *sm.defValue, sm.separator, sm.layout); err != nil {
+			problems = append(problems, fmt.Sprintf("field %q: invalid %s value %q: %v", path, TagEnvDefault, *sm.defValue, err))
+		}
+	}
+
+	for _, option := range sm.oneOf {
+		value := reflect.New(valueType).Elem()
+		if err := parseValue(value, option, sm.separator, sm.layout); err != nil {
+			problems = append(problems, fmt.Sprintf("field %q: invalid %s value %q: %v", path, TagEnvOneOf, option, err))
+		}
+	}
+
+	if sm.pattern != nil {
+		if _, err := regexp.Compile(*sm.pattern); err != nil {
+			problems = append(problems, fmt.Sprintf("field %q: invalid %s value %q: %v", path, TagEnvPattern, *sm.pattern, err))
+		}
+	}
+
+	return problems
+}
+
+// supportedType determines if parseValue can parse a value of the type
+func supportedType(t reflect.Type) bool {
+	if _, found := validStructs[t]; found {
+		return true
+	}
+
+	ptr := reflect.PtrTo(t)
+	if t.Implements(textUnmarshalerType) || ptr.Implements(textUnmarshalerType) ||
+		t.Implements(setterType) || ptr.Implements(setterType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8 || supportedType(t.Elem())
+	case reflect.Map:
+		return supportedType(t.Key()) && supportedType(t.Elem())
+	}
+
+	return false
+}
+
+// validLayout determines if the time layout contains any layout elements and can parse its own output
+func validLayout(layout string) bool {
+	// the time must differ from the reference time in every element
+	ref := time.Date(2001, time.February, 3, 4, 5, 6, 0, time.UTC)
+	text := ref.Format(layout)
+	if text == layout {
+		return false
+	}
+	_, err := time.Parse(layout, text)
+	return err == nil
+}
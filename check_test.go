@@ -0,0 +1,113 @@
+package cleanenv
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+// checkSetter is a structure parsed with a custom setter
+type checkSetter struct {
+	value string
+}
+
+func (s *checkSetter) SetValue(value string) error {
+	s.value = value
+	return nil
+}
+
+func TestCheck(t *testing.T) {
+	type database struct {
+		Host string `env:"HOST"`
+		Port int    `env:"PORT" env-default:"5432"`
+	}
+
+	type valid struct {
+		Database database          `env-prefix:"DB_"`
+		Timeout  time.Duration     `env:"TIMEOUT" env-default:"5s"`
+		Started  time.Time         `env:"STARTED" env-default:"2020-01-02" env-layout:"2006-01-02"`
+		URL      url.URL           `env:"URL"`
+		Hosts    []string          `env:"HOSTS" env-separator:";"`
+		Labels   map[string]int    `env:"LABELS" env-default:"a:1,b:2"`
+		Level    string            `env:"LEVEL" env-oneof:"debug,info" env-pattern:"^[a-z]+$"`
+		Custom   checkSetter       `env:"CUSTOM"`
+		Events   chan string       `yaml:"events"`
+		Raw      []byte            `env:"RAW"`
+		Location *time.Location    `env:"LOCATION" env-default:"UTC"`
+		Nested   map[string][]bool `env-default:""`
+	}
+
+	type invalid struct {
+		Database database          `env-prefix:"DB_"`
+		Port     int               `env:"PORT" env-default:"port"`
+		Started  time.Time         `env:"STARTED" env-layout:"YYYY-MM-DD"`
+		Timeout  time.Duration     `env:"TIMEOUT" env-layout:"2006"`
+		Events   chan string       `env:"EVENTS"`
+		Hosts    []string          `env:"HOSTS" env-separator:""`
+		Labels   map[string]string `env:"LABELS" env-separator:":"`
+		Level    int               `env:"LEVEL" env-oneof:"1,debug"`
+		Name     string            `env:"NAME" env-pattern:"[a-z"`
+		Address  string            `env:"DB_HOST"`
+	}
+
+	tests := []struct {
+		name string
+		cfg  interface{}
+		want string
+	}{
+		{
+			name: "valid",
+			cfg:  &valid{},
+		},
+		{
+			name: "valid structure",
+			cfg:  valid{},
+		},
+		{
+			name: "invalid",
+			cfg:  &invalid{},
+			want: `field "Port": invalid env-default value "port": strconv.ParseInt: parsing "port": invalid syntax; ` +
+				`field "Started": invalid env-layout value "YYYY-MM-DD"; ` +
+				`field "Timeout": env-layout is not supported for type time.Duration; ` +
+				`field "Events": type chan string is not supported; ` +
+				`field "Hosts": env-separator is empty; ` +
+				`field "Labels": env-separator ":" collides with the map key-value delimiter; ` +
+				`field "Level": invalid env-oneof value "debug": strconv.ParseInt: parsing "debug": invalid syntax; ` +
+				"field \"Name\": invalid env-pattern value \"[a-z\": error parsing regexp: missing closing ]: `[a-z`; " +
+				`environment variable "DB_HOST" is used by fields "Address" and "Database.Host"`,
+		},
+		{
+			name: "not a structure",
+			cfg:  42,
+			want: "wrong type int, structure expected",
+		},
+		{
+			name: "nil",
+			cfg:  nil,
+			want: "wrong type <nil>, structure expected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(tt.cfg)
+			var got string
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.want {
+				t.Errorf("wrong error\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("not modified", func(t *testing.T) {
+		cfg := valid{Level: "debug"}
+		if err := Check(&cfg); err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Timeout != 0 || cfg.Level != "debug" {
+			t.Errorf("configuration is modified: %+v", cfg)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package cleanenv
 
 import (
+	"bytes"
 	"encoding"
 	"encoding/json"
 	"flag"
@@ -10,10 +11,10 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/BurntSushi/toml"
 	"github.com/joho/godotenv"
@@ -51,6 +52,36 @@ const (
 
 	// TagEnvPrefix flag to specify prefix for structure fields
 	TagEnvPrefix = "env-prefix"
+
+	// TagEnvMin minimal value (or length for strings, slices and maps)
+	TagEnvMin = "env-min"
+
+	// TagEnvMax maximal value (or length for strings, slices and maps)
+	TagEnvMax = "env-max"
+
+	// TagEnvOneOf list of allowed values
+	TagEnvOneOf = "env-oneof"
+
+	// TagEnvPattern regular expression the value must match
+	TagEnvPattern = "env-pattern"
+
+	// TagEnvLen exact length of a string, slice or map
+	TagEnvLen = "env-len"
+
+	// TagEnvRequiredIf condition to mark a field as required, e.g. "TLSEnabled=true"
+	TagEnvRequiredIf = "env-required-if"
+
+	// TagEnvRequiredWith list of fields which make a field required if any of them is set
+	TagEnvRequiredWith = "env-required-with"
+
+	// TagEnvExclusiveGroup name of a group of mutually exclusive fields
+	TagEnvExclusiveGroup = "env-exclusive-group"
+
+	// TagEnvSecret flag to mark a field as secret, its value is redacted in the output
+	TagEnvSecret = "env-secret"
+
+	// TagEnvDeprecated deprecated environment variable name or a list of names, still accepted with a warning
+	TagEnvDeprecated = "env-deprecated"
 )
 
 // Setter is an interface for a custom value setter.
@@ -75,6 +106,42 @@ type Updater interface {
 	Update() error
 }
 
+// Validator gives an ability to implement custom validation for a structure.
+//
+// Validate is called on the root structure and on every nested structure after all values are set.
+// Nested structures are validated before their parents:
+//
+//	type DBConfig struct {
+//		Host string `env:"HOST"`
+//		Port int    `env:"PORT"`
+//	}
+//
+//	func (c *DBConfig) Validate() error {
+//		if c.Host == "" && c.Port != 0 {
+//			return fmt.Errorf("port is set without a host")
+//		}
+//		return nil
+//	}
+type Validator interface {
+	Validate() error
+}
+
+// Defaulter gives an ability to set default values for a structure before any source is read.
+// SetDefaults is not called by UpdateEnv.
+type Defaulter interface {
+	SetDefaults() error
+}
+
+// PostLoader gives an ability to process a structure after each source (configuration file, environment variables) is read
+type PostLoader interface {
+	PostLoad() error
+}
+
+// Finalizer gives an ability to process a structure after the configuration is read and validated
+type Finalizer interface {
+	Finalize() error
+}
+
 // ReadConfig reads configuration file and parses it depending on tags in structure provided.
 // Then it reads and parses
 //
@@ -94,23 +161,75 @@ type Updater interface {
 //	if err != nil {
 //	    ...
 //	}
-func ReadConfig(path string, cfg interface{}) error {
-	err := parseFile(path, cfg)
+func ReadConfig(path string, cfg interface{}, opts ...Option) error {
+	o := newOptions(opts)
+
+	nodes, metaInfo, err := readStructTree(cfg, o)
 	if err != nil {
 		return err
 	}
 
-	return readEnvVars(cfg, false)
+	if err = setDefaults(nodes); err != nil {
+		return err
+	}
+
+	if err = applyDefaults(metaInfo, false, o); err != nil {
+		return err
+	}
+
+	file, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err = o.checkFileKeys(file, cfg); err != nil {
+		return err
+	}
+
+	if err = parseReader(path, bytes.NewReader(file.data), cfg); err != nil {
+		return err
+	}
+
+	inFile := file.fields(cfg, metaInfo)
+	o.recordFile(path, cfg, metaInfo, inFile)
+	for i := range metaInfo {
+		if inFile[i] {
+			metaInfo[i].set = true
+		}
+	}
+
+	if err = postLoad(nodes); err != nil {
+		return err
+	}
+
+	if err = applyEnv(cfg, nodes, metaInfo, false, o); err != nil {
+		return err
+	}
+
+	if err = finish(nodes, metaInfo); err != nil {
+		return err
+	}
+
+	o.fillReport(metaInfo)
+	return nil
 }
 
 // ReadEnv reads environment variables into the structure.
-func ReadEnv(cfg interface{}) error {
-	return readEnvVars(cfg, false)
+func ReadEnv(cfg interface{}, opts ...Option) error {
+	return readEnvVars(cfg, false, opts...)
 }
 
 // UpdateEnv rereads (updates) environment variables in the structure.
-func UpdateEnv(cfg interface{}) error {
-	return readEnvVars(cfg, true)
+// Functions registered by OnChange are called for the changed fields.
+func UpdateEnv(cfg interface{}, opts ...Option) error {
+	old := changeSnapshot(cfg)
+
+	if err := readEnvVars(cfg, true, opts...); err != nil {
+		return err
+	}
+
+	notifyChanges(cfg, old)
+	return nil
 }
 
 // parseFile parses configuration file according to its extension
@@ -134,18 +253,23 @@ func parseFile(path string, cfg interface{}) error {
 	}
 	defer f.Close()
 
-	// parse the file depending on the file type
+	return parseReader(path, f, cfg)
+}
+
+// parseReader parses the configuration file content depending on the file type
+func parseReader(path string, r io.Reader, cfg interface{}) error {
+	var err error
 	switch ext := strings.ToLower(filepath.Ext(path)); ext {
 	case ".yaml", ".yml":
-		err = ParseYAML(f, cfg)
+		err = ParseYAML(r, cfg)
 	case ".json":
-		err = ParseJSON(f, cfg)
+		err = ParseJSON(r, cfg)
 	case ".toml":
-		err = ParseTOML(f, cfg)
+		err = ParseTOML(r, cfg)
 	case ".edn":
-		err = parseEDN(f, cfg)
+		err = parseEDN(r, cfg)
 	case ".env":
-		err = parseENV(f, cfg)
+		err = parseENV(r, cfg)
 	default:
 		return fmt.Errorf("file format '%s' doesn't supported by the parser", ext)
 	}
@@ -155,6 +279,40 @@ func parseFile(path string, cfg interface{}) error {
 	return nil
 }
 
+// fileFormat returns the configuration file format by the file extension
+func fileFormat(path string) string {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".json", ".toml", ".edn", ".env":
+		return ext[1:]
+	}
+	return ""
+}
+
+// fileKey returns the key of the structure field in the configuration file of the given format.
+// The key is taken from the format tag (e.g. `yaml:"key"`) or derived from the field name the same way as the format decoder does.
+// It returns false if the field is skipped by the decoder.
+func fileKey(field reflect.StructField, format string) (string, bool) {
+	name := strings.Split(field.Tag.Get(format), ",")[0]
+	if name == "-" {
+		return "", false
+	}
+	if name != "" {
+		return name, true
+	}
+
+	switch format {
+	case "yaml":
+		return strings.ToLower(field.Name), true
+	case "edn":
+		r := []rune(field.Name)
+		r[0] = unicode.ToLower(r[0])
+		return string(r), true
+	}
+	return field.Name, true
+}
+
 // ParseYAML parses YAML from reader to data structure
 func ParseYAML(r io.Reader, str interface{}) error {
 	return yaml.NewDecoder(r).Decode(str)
@@ -241,15 +399,31 @@ func parseMap(valueType reflect.Type, value string, sep string, layout *string)
 // structMeta is a structure metadata entity
 type structMeta struct {
 	envList     []string
+	deprecated  []string
 	fieldName   string
 	fieldValue  reflect.Value
+	fieldTag    reflect.StructTag
 	defValue    *string
 	layout      *string
 	separator   string
 	description string
 	updatable   bool
 	required    bool
+	secret      bool
 	path        string
+	prefix      string
+	min         *string
+	max         *string
+	length      *string
+	pattern     *string
+	oneOf       []string
+
+	requiredIf     *string
+	requiredWith   []string
+	exclusiveGroup string
+
+	// set shows that the value was set by a source: a default value, the configuration file or an environment variable
+	set bool
 }
 
 // isFieldValueZero determines if fieldValue empty or not
@@ -257,6 +431,13 @@ func (sm *structMeta) isFieldValueZero() bool {
 	return sm.fieldValue.IsZero()
 }
 
+// envNames returns the current and the deprecated environment variable names of the field
+func (sm *structMeta) envNames() []string {
+	names := make([]string, 0, len(sm.envList)+len(sm.deprecated))
+	names = append(names, sm.envList...)
+	return append(names, sm.deprecated...)
+}
+
 // parseFunc custom value parser function
 type parseFunc func(*reflect.Value, string, *string) error
 
@@ -298,15 +479,25 @@ var validStructs = map[reflect.Type]parseFunc{
 	},
 }
 
+// cfgNode is a configuration structure or a nested structure
+type cfgNode struct {
+	Val    interface{}
+	Prefix string
+	Path   string
+	// AutoPrefix is the prefix of environment variable names derived from field names, see WithAutoEnv
+	AutoPrefix string
+}
+
 // readStructMetadata reads structure metadata (types, tags, etc.)
 func readStructMetadata(cfgRoot interface{}) ([]structMeta, error) {
-	type cfgNode struct {
-		Val    interface{}
-		Prefix string
-		Path   string
-	}
+	_, metas, err := readStructTree(cfgRoot, newOptions(nil))
+	return metas, err
+}
 
-	cfgStack := []cfgNode{{cfgRoot, "", ""}}
+// readStructTree reads the list of nested structures and the structure metadata.
+// Nested structures are listed after their parents.
+func readStructTree(cfgRoot interface{}, o *options) ([]cfgNode, []structMeta, error) {
+	cfgStack := []cfgNode{{Val: cfgRoot, Prefix: o.prefix, AutoPrefix: o.prefix}}
 	metas := make([]structMeta, 0)
 
 	for i := 0; i < len(cfgStack); i++ {
@@ -321,7 +512,7 @@ func readStructMetadata(cfgRoot interface{}) ([]structMeta, error) {
 
 		// process only structures
 		if s.Kind() != reflect.Struct {
-			return nil, fmt.Errorf("wrong type %v", s.Kind())
+			return nil, nil, fmt.Errorf("wrong type %v", s.Kind())
 		}
 		typeInfo := s.Type()
 
@@ -345,9 +536,10 @@ func readStructMetadata(cfgRoot interface{}) ([]structMeta, error) {
 				if _, found := validStructs[fld.Type()]; !found {
 					prefix, _ := fType.Tag.Lookup(TagEnvPrefix)
 					cfgStack = append(cfgStack, cfgNode{
-						Val:    fld.Addr().Interface(),
-						Prefix: sPrefix + prefix,
-						Path:   fmt.Sprintf("%s%s.", cfgStack[i].Path, fType.Name),
+						Val:        fld.Addr().Interface(),
+						Prefix:     sPrefix + prefix,
+						Path:       fmt.Sprintf("%s%s.", cfgStack[i].Path, fType.Name),
+						AutoPrefix: o.autoPrefix(cfgStack[i], fType, sPrefix+prefix),
 					})
 					continue
 				}
@@ -377,60 +569,206 @@ func readStructMetadata(cfgRoot interface{}) ([]structMeta, error) {
 
 			_, required := fType.Tag.Lookup(TagEnvRequired)
 
+			_, secret := fType.Tag.Lookup(TagEnvSecret)
+
+			var oneOf []string
+			if values, ok := fType.Tag.Lookup(TagEnvOneOf); ok {
+				oneOf = strings.Split(values, DefaultSeparator)
+				for i := range oneOf {
+					oneOf[i] = strings.TrimSpace(oneOf[i])
+				}
+			}
+
+			var requiredWith []string
+			if fields, ok := fType.Tag.Lookup(TagEnvRequiredWith); ok && len(fields) != 0 {
+				requiredWith = strings.Split(fields, DefaultSeparator)
+				for i := range requiredWith {
+					requiredWith[i] = strings.TrimSpace(requiredWith[i])
+				}
+			}
+
 			envList := make([]string, 0)
 
-			if envs, ok := fType.Tag.Lookup(TagEnv); ok && len(envs) != 0 {
+			if envs, ok := fType.Tag.Lookup(TagEnv); ok && len(envs) != 0 && envs != envSkip {
 				envList = strings.Split(envs, DefaultSeparator)
 				if sPrefix != "" {
 					for i := range envList {
 						envList[i] = sPrefix + envList[i]
 					}
 				}
+			} else if !ok && o.autoEnv {
+				envList = append(envList, cfgStack[i].AutoPrefix+envName(fType.Name))
+			}
+
+			var deprecated []string
+			if envs, ok := fType.Tag.Lookup(TagEnvDeprecated); ok && len(envs) != 0 {
+				deprecated = strings.Split(envs, DefaultSeparator)
+				for i := range deprecated {
+					deprecated[i] = sPrefix + strings.TrimSpace(deprecated[i])
+				}
 			}
 
 			metas = append(metas, structMeta{
 				envList:     envList,
+				deprecated:  deprecated,
 				fieldName:   s.Type().Field(idx).Name,
 				fieldValue:  s.Field(idx),
+				fieldTag:    fType.Tag,
 				defValue:    defValue,
 				layout:      layout,
 				separator:   separator,
 				description: fType.Tag.Get(TagEnvDescription),
 				updatable:   upd,
 				required:    required,
+				secret:      secret,
 				path:        cfgStack[i].Path,
+				prefix:      sPrefix,
+				min:         lookupTag(fType.Tag, TagEnvMin),
+				max:         lookupTag(fType.Tag, TagEnvMax),
+				length:      lookupTag(fType.Tag, TagEnvLen),
+				pattern:     lookupTag(fType.Tag, TagEnvPattern),
+				oneOf:       oneOf,
+
+				requiredIf:     lookupTag(fType.Tag, TagEnvRequiredIf),
+				requiredWith:   requiredWith,
+				exclusiveGroup: fType.Tag.Get(TagEnvExclusiveGroup),
 			})
 		}
 
 	}
 
-	return metas, nil
+	return cfgStack, metas, nil
+}
+
+// duplicateEnv returns the descriptions of environment variables used by more than one field.
+// Fields of nested structures with colliding prefixes may get the same variable names.
+func duplicateEnv(metaInfo []structMeta) []string {
+	fields := make(map[string]string)
+	problems := make([]string, 0)
+
+	for _, meta := range metaInfo {
+		path := meta.path + meta.fieldName
+		for _, env := range meta.envNames() {
+			if other, ok := fields[env]; ok && other != path {
+				problems = append(problems, fmt.Sprintf("environment variable %q is used by fields %q and %q", env, other, path))
+				continue
+			}
+			fields[env] = path
+		}
+	}
+
+	return problems
+}
+
+// lookupTag returns a pointer to the tag value or nil if the tag is not set
+func lookupTag(tag reflect.StructTag, key string) *string {
+	if value, ok := tag.Lookup(key); ok {
+		return &value
+	}
+	return nil
 }
 
 // readEnvVars reads environment variables to the provided configuration structure
-func readEnvVars(cfg interface{}, update bool) error {
-	metaInfo, err := readStructMetadata(cfg)
+func readEnvVars(cfg interface{}, update bool, opts ...Option) error {
+	o := newOptions(opts)
+
+	nodes, metaInfo, err := readStructTree(cfg, o)
 	if err != nil {
 		return err
 	}
 
+	if !update {
+		if err = setDefaults(nodes); err != nil {
+			return err
+		}
+	}
+
+	if err = applyDefaults(metaInfo, update, o); err != nil {
+		return err
+	}
+
+	if err = applyEnv(cfg, nodes, metaInfo, update, o); err != nil {
+		return err
+	}
+
+	if err = finish(nodes, metaInfo); err != nil {
+		return err
+	}
+
+	o.fillReport(metaInfo)
+	return nil
+}
+
+// applyDefaults sets default values of empty fields before any source is read,
+// so a value explicitly set to zero by a source is not overwritten by the default.
+//
+// Maps are skipped because file decoders merge map items into the existing map.
+// Their default values are applied after all sources if the map is still empty.
+// In update mode only updatable fields get their default values.
+func applyDefaults(metaInfo []structMeta, update bool, o *options) error {
+	for i := range metaInfo {
+		meta := &metaInfo[i]
+		if update && !meta.updatable {
+			continue
+		}
+		if meta.defValue == nil || meta.required || meta.fieldValue.Kind() == reflect.Map || !meta.isFieldValueZero() {
+			continue
+		}
+
+		if err := parseValue(meta.fieldValue, *meta.defValue, meta.separator, meta.layout); err != nil {
+			var envName string
+			if len(meta.envList) > 0 {
+				envName = meta.envList[0]
+			}
+			return fmt.Errorf("parsing field %q env %q: %v",
+				meta.path+meta.fieldName, envName, err,
+			)
+		}
+		meta.set = true
+		o.record(meta, Source{Kind: SourceDefault})
+	}
+	return nil
+}
+
+// applyEnv reads environment variables into the structure fields
+func applyEnv(cfg interface{}, nodes []cfgNode, metaInfo []structMeta, update bool, o *options) error {
 	if updater, ok := cfg.(Updater); ok {
-		if err = updater.Update(); err != nil {
+		if err := updater.Update(); err != nil {
 			return err
 		}
 	}
 
-	for _, meta := range metaInfo {
+	if err := o.check(o.duplicateEnv, duplicateEnv(metaInfo)); err != nil {
+		return err
+	}
+
+	if err := o.checkEnv(nodes, metaInfo); err != nil {
+		return err
+	}
+
+	lookupEnv := o.envLookup()
+
+	for i := range metaInfo {
+		meta := &metaInfo[i]
+
 		// update only updatable fields
 		if update && !meta.updatable {
 			continue
 		}
 
-		var rawValue *string
+		var (
+			rawValue *string
+			source   = Source{Kind: SourceEnv}
+		)
 
 		for _, env := range meta.envList {
-			if value, ok := os.LookupEnv(env); ok {
+			key, value, ok, err := lookupEnv(env)
+			if err != nil {
+				return fmt.Errorf("reading field %q: %v", meta.path+meta.fieldName, err)
+			}
+			if ok {
 				rawValue = &value
+				source.Name = key
 				break
 			}
 		}
@@ -440,27 +778,117 @@ func readEnvVars(cfg interface{}, update bool) error {
 			envName = meta.envList[0]
 		}
 
-		if rawValue == nil && meta.required && meta.isFieldValueZero() {
+		// deprecated names are accepted only if none of the current names is set
+		for _, env := range meta.deprecated {
+			if rawValue != nil {
+				break
+			}
+			key, value, ok, err := lookupEnv(env)
+			if err != nil {
+				return fmt.Errorf("reading field %q: %v", meta.path+meta.fieldName, err)
+			}
+			if ok {
+				rawValue = &value
+				source.Name = key
+				o.warnDeprecated(key, envName)
+			}
+		}
+
+		// required fields of an exclusive group are checked together with the group
+		if rawValue == nil && meta.required && meta.exclusiveGroup == "" && meta.isFieldValueZero() {
 			return fmt.Errorf("field %q is required but the value is not provided",
 				meta.path+meta.fieldName,
 			)
 		}
 
-		if rawValue == nil && meta.isFieldValueZero() {
+		// defaults of maps are applied after all sources, see applyDefaults
+		if rawValue == nil && meta.fieldValue.Kind() == reflect.Map && meta.isFieldValueZero() {
 			rawValue = meta.defValue
+			source = Source{Kind: SourceDefault}
 		}
 
 		if rawValue == nil {
 			continue
 		}
 
-		if err = parseValue(meta.fieldValue, *rawValue, meta.separator, meta.layout); err != nil {
+		if err := parseValue(meta.fieldValue, *rawValue, meta.separator, meta.layout); err != nil {
 			return fmt.Errorf("parsing field %q env %q: %v",
 				meta.path+meta.fieldName, envName, err,
 			)
 		}
+		meta.set = true
+		o.record(meta, source)
 	}
 
+	return postLoad(nodes)
+}
+
+// finish checks the loaded configuration and finalizes it
+func finish(nodes []cfgNode, metaInfo []structMeta) error {
+	if err := checkRequirements(metaInfo); err != nil {
+		return err
+	}
+
+	for _, meta := range metaInfo {
+		if err := meta.validate(); err != nil {
+			var envName string
+			if len(meta.envList) > 0 {
+				envName = meta.envList[0]
+			}
+			return fmt.Errorf("validating field %q env %q: %v",
+				meta.path+meta.fieldName, envName, err,
+			)
+		}
+	}
+
+	if err := walkStructs(nodes, "validating", func(s interface{}) error {
+		if validator, ok := s.(Validator); ok {
+			return validator.Validate()
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return walkStructs(nodes, "finalizing", func(s interface{}) error {
+		if finalizer, ok := s.(Finalizer); ok {
+			return finalizer.Finalize()
+		}
+		return nil
+	})
+}
+
+// setDefaults calls SetDefaults on every structure implementing Defaulter
+func setDefaults(nodes []cfgNode) error {
+	return walkStructs(nodes, "setting defaults of", func(s interface{}) error {
+		if defaulter, ok := s.(Defaulter); ok {
+			return defaulter.SetDefaults()
+		}
+		return nil
+	})
+}
+
+// postLoad calls PostLoad on every structure implementing PostLoader
+func postLoad(nodes []cfgNode) error {
+	return walkStructs(nodes, "post-loading", func(s interface{}) error {
+		if postLoader, ok := s.(PostLoader); ok {
+			return postLoader.PostLoad()
+		}
+		return nil
+	})
+}
+
+// walkStructs calls the function for every structure, nested structures go before their parents.
+// Errors of nested structures are prefixed with the action and the structure path.
+func walkStructs(nodes []cfgNode, action string, fn func(interface{}) error) error {
+	for i := len(nodes) - 1; i >= 0; i-- {
+		if err := fn(nodes[i].Val); err != nil {
+			if nodes[i].Path == "" {
+				return err
+			}
+			return fmt.Errorf("%s %q: %w", action, strings.TrimSuffix(nodes[i].Path, "."), err)
+		}
+	}
 	return nil
 }
 
@@ -574,8 +1002,13 @@ func parseValue(field reflect.Value, value, sep string, layout *string) error {
 
 // GetDescription returns a description of environment variables.
 // You can provide a custom header text.
-func GetDescription(cfg interface{}, headerText *string) (string, error) {
-	meta, err := readStructMetadata(cfg)
+//
+// Variables are grouped by structure and listed in the order of declaration.
+// The description is rendered with DefaultUsageTemplate, use WithUsageTemplate option to replace it.
+func GetDescription(cfg interface{}, headerText *string, opts ...Option) (string, error) {
+	o := newOptions(opts)
+
+	nodes, meta, err := readStructTree(cfg, o)
 	if err != nil {
 		return "", err
 	}
@@ -588,34 +1021,12 @@ func GetDescription(cfg interface{}, headerText *string) (string, error) {
 		header = "Environment variables:"
 	}
 
-	description := make([]string, 0)
-
-	for _, m := range meta {
-		if len(m.envList) == 0 {
-			continue
-		}
-
-		for idx, env := range m.envList {
-
-			elemDescription := fmt.Sprintf("\n  %s %s", env, m.fieldValue.Kind())
-			if idx > 0 {
-				elemDescription += fmt.Sprintf(" (alternative to %s)", m.envList[0])
-			}
-			elemDescription += fmt.Sprintf("\n    \t%s", m.description)
-			if m.defValue != nil {
-				elemDescription += fmt.Sprintf(" (default %q)", *m.defValue)
-			}
-			description = append(description, elemDescription)
-		}
-	}
-
-	if len(description) == 0 {
+	data := usageData(header, nodes, meta)
+	if len(data.Groups) == 0 {
 		return "", nil
 	}
 
-	sort.Strings(description)
-
-	return header + strings.Join(description, ""), nil
+	return executeUsage(o.usageTemplate, data)
 }
 
 // Usage returns a configuration usage help.
@@ -901,8 +901,8 @@ func TestGetDescription(t *testing.T) {
 			header: nil,
 			want: "Environment variables:" +
 				"\n  ONE int\n    \tone" +
-				"\n  THREE int\n    \tthree" +
-				"\n  TWO int\n    \ttwo",
+				"\n  TWO int\n    \ttwo" +
+				"\n  THREE int\n    \tthree",
 			wantErr: false,
 		},
 
@@ -911,10 +911,10 @@ func TestGetDescription(t *testing.T) {
 			cfg:    &testSeveralEnv{},
 			header: nil,
 			want: "Environment variables:" +
-				"\n  ENO int (alternative to ONE)\n    \tone" +
 				"\n  ONE int\n    \tone" +
-				"\n  OWT int (alternative to TWO)\n    \ttwo" +
-				"\n  TWO int\n    \ttwo",
+				"\n  ENO int (alternative to ONE)\n    \tone" +
+				"\n  TWO int\n    \ttwo" +
+				"\n  OWT int (alternative to TWO)\n    \ttwo",
 			wantErr: false,
 		},
 
@@ -924,8 +924,8 @@ func TestGetDescription(t *testing.T) {
 			header: nil,
 			want: "Environment variables:" +
 				"\n  ONE int\n    \tone (default \"1\")" +
-				"\n  THREE int\n    \tthree (default \"3\")" +
-				"\n  TWO int\n    \ttwo (default \"2\")",
+				"\n  TWO int\n    \ttwo (default \"2\")" +
+				"\n  THREE int\n    \tthree (default \"3\")",
 			wantErr: false,
 		},
 
@@ -934,7 +934,9 @@ func TestGetDescription(t *testing.T) {
 			cfg:    &testDeep{},
 			header: nil,
 			want: "Environment variables:" +
+				"\n\nOneStruct:" +
 				"\n  ONE int\n    \tone" +
+				"\n\nTwoStruct:" +
 				"\n  TWO int\n    \ttwo",
 			wantErr: false,
 		},
@@ -953,8 +955,8 @@ func TestGetDescription(t *testing.T) {
 			header: &header,
 			want: "test header:" +
 				"\n  ONE int\n    \tone" +
-				"\n  THREE int\n    \tthree" +
-				"\n  TWO int\n    \ttwo",
+				"\n  TWO int\n    \ttwo" +
+				"\n  THREE int\n    \tthree",
 			wantErr: false,
 		},
 
@@ -1002,8 +1004,8 @@ func TestFUsage(t *testing.T) {
 			usageTexts: nil,
 			want: "Environment variables:" +
 				"\n  ONE int\n    \tone" +
-				"\n  THREE int\n    \tthree" +
 				"\n  TWO int\n    \ttwo" +
+				"\n  THREE int\n    \tthree" +
 				"\n",
 		},
 
@@ -1013,8 +1015,8 @@ func TestFUsage(t *testing.T) {
 			usageTexts: nil,
 			want: "test header:" +
 				"\n  ONE int\n    \tone" +
-				"\n  THREE int\n    \tthree" +
 				"\n  TWO int\n    \ttwo" +
+				"\n  THREE int\n    \tthree" +
 				"\n",
 		},
 
@@ -1029,8 +1031,8 @@ func TestFUsage(t *testing.T) {
 			want: "test1\ntest2\ntest3\n" +
 				"\nEnvironment variables:" +
 				"\n  ONE int\n    \tone" +
-				"\n  THREE int\n    \tthree" +
 				"\n  TWO int\n    \ttwo" +
+				"\n  THREE int\n    \tthree" +
 				"\n",
 		},
 
@@ -1045,8 +1047,8 @@ func TestFUsage(t *testing.T) {
 			want: "test1\ntest2\ntest3\n" +
 				"\ntest header:" +
 				"\n  ONE int\n    \tone" +
-				"\n  THREE int\n    \tthree" +
 				"\n  TWO int\n    \ttwo" +
+				"\n  THREE int\n    \tthree" +
 				"\n",
 		},
 	}
@@ -1102,9 +1104,13 @@ func TestFUsageNested(t *testing.T) {
 			headerText: nil,
 			usageTexts: nil,
 			want: "Environment variables:" +
-				"\n  APP_CACHE_REDIS_HOST string\n    \tredis host" +
-				"\n  APP_CACHE_TYPE string\n    \tcache type" +
+				"\n\nApp (APP_):" +
 				"\n  APP_PORT int\n    \tapp port" +
+				"\n\nApp.Cache (APP_CACHE_):" +
+				"\n  APP_CACHE_TYPE string\n    \tcache type" +
+				"\n\nApp.Cache.Redis (APP_CACHE_REDIS_):" +
+				"\n  APP_CACHE_REDIS_HOST string\n    \tredis host" +
+				"\n\nDatabase (DATABASE_):" +
 				"\n  DATABASE_HOST string\n    \tdatabase host" +
 				"\n",
 		},
@@ -1114,9 +1120,13 @@ func TestFUsageNested(t *testing.T) {
 			headerText: &customHeader,
 			usageTexts: nil,
 			want: "test header:" +
-				"\n  APP_CACHE_REDIS_HOST string\n    \tredis host" +
-				"\n  APP_CACHE_TYPE string\n    \tcache type" +
+				"\n\nApp (APP_):" +
 				"\n  APP_PORT int\n    \tapp port" +
+				"\n\nApp.Cache (APP_CACHE_):" +
+				"\n  APP_CACHE_TYPE string\n    \tcache type" +
+				"\n\nApp.Cache.Redis (APP_CACHE_REDIS_):" +
+				"\n  APP_CACHE_REDIS_HOST string\n    \tredis host" +
+				"\n\nDatabase (DATABASE_):" +
 				"\n  DATABASE_HOST string\n    \tdatabase host" +
 				"\n",
 		},
@@ -1131,9 +1141,13 @@ func TestFUsageNested(t *testing.T) {
 			},
 			want: "test1\ntest2\ntest3\n" +
 				"\nEnvironment variables:" +
-				"\n  APP_CACHE_REDIS_HOST string\n    \tredis host" +
-				"\n  APP_CACHE_TYPE string\n    \tcache type" +
+				"\n\nApp (APP_):" +
 				"\n  APP_PORT int\n    \tapp port" +
+				"\n\nApp.Cache (APP_CACHE_):" +
+				"\n  APP_CACHE_TYPE string\n    \tcache type" +
+				"\n\nApp.Cache.Redis (APP_CACHE_REDIS_):" +
+				"\n  APP_CACHE_REDIS_HOST string\n    \tredis host" +
+				"\n\nDatabase (DATABASE_):" +
 				"\n  DATABASE_HOST string\n    \tdatabase host" +
 				"\n",
 		},
@@ -1148,9 +1162,13 @@ func TestFUsageNested(t *testing.T) {
 			},
 			want: "test1\ntest2\ntest3\n" +
 				"\ntest header:" +
-				"\n  APP_CACHE_REDIS_HOST string\n    \tredis host" +
-				"\n  APP_CACHE_TYPE string\n    \tcache type" +
+				"\n\nApp (APP_):" +
 				"\n  APP_PORT int\n    \tapp port" +
+				"\n\nApp.Cache (APP_CACHE_):" +
+				"\n  APP_CACHE_TYPE string\n    \tcache type" +
+				"\n\nApp.Cache.Redis (APP_CACHE_REDIS_):" +
+				"\n  APP_CACHE_REDIS_HOST string\n    \tredis host" +
+				"\n\nDatabase (DATABASE_):" +
 				"\n  DATABASE_HOST string\n    \tdatabase host" +
 				"\n",
 		},
@@ -1578,3 +1596,273 @@ two = 2`),
 		})
 	}
 }
+
+type testHooksNested struct {
+	Value string `yaml:"value" env:"TEST_HOOKS_NESTED"`
+	calls *[]string
+}
+
+func (c *testHooksNested) SetDefaults() error {
+	*c.calls = append(*c.calls, "nested defaults: "+c.Value)
+	c.Value = "default"
+	return nil
+}
+
+func (c *testHooksNested) PostLoad() error {
+	*c.calls = append(*c.calls, "nested post load: "+c.Value)
+	return nil
+}
+
+func (c *testHooksNested) Finalize() error {
+	*c.calls = append(*c.calls, "nested finalize: "+c.Value)
+	return nil
+}
+
+type testHooks struct {
+	Nested testHooksNested `yaml:"nested"`
+	Value  string          `yaml:"value" env:"TEST_HOOKS_VALUE"`
+	calls  *[]string
+	err    error
+}
+
+func (c *testHooks) SetDefaults() error {
+	*c.calls = append(*c.calls, "root defaults: "+c.Nested.Value)
+	return nil
+}
+
+func (c *testHooks) PostLoad() error {
+	*c.calls = append(*c.calls, "root post load: "+c.Value)
+	return c.err
+}
+
+func (c *testHooks) Validate() error {
+	*c.calls = append(*c.calls, "root validate: "+c.Value)
+	return nil
+}
+
+func (c *testHooks) Finalize() error {
+	*c.calls = append(*c.calls, "root finalize: "+c.Value)
+	return nil
+}
+
+func TestHooksOrder(t *testing.T) {
+	tmpFile, err := ioutil.TempFile(os.TempDir(), "*.yml")
+	if err != nil {
+		t.Fatal("cannot create temporary file:", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err = tmpFile.Write([]byte("value: file\nnested:\n  value: file\n")); err != nil {
+		t.Fatal("failed to write to temporary file:", err)
+	}
+
+	os.Setenv("TEST_HOOKS_VALUE", "env")
+	defer os.Clearenv()
+
+	t.Run("read config", func(t *testing.T) {
+		calls := make([]string, 0)
+		cfg := testHooks{calls: &calls, Nested: testHooksNested{calls: &calls}}
+
+		if err := ReadConfig(tmpFile.Name(), &cfg); err != nil {
+			t.Fatal(err)
+		}
+
+		want := []string{
+			"nested defaults: ",
+			"root defaults: default",
+			"nested post load: file",
+			"root post load: file",
+			"nested post load: file",
+			"root post load: env",
+			"root validate: env",
+			"nested finalize: file",
+			"root finalize: env",
+		}
+		if !reflect.DeepEqual(calls, want) {
+			t.Errorf("wrong call order %v, want %v", calls, want)
+		}
+	})
+
+	t.Run("update env", func(t *testing.T) {
+		calls := make([]string, 0)
+		cfg := testHooks{calls: &calls, Nested: testHooksNested{calls: &calls}}
+
+		if err := UpdateEnv(&cfg); err != nil {
+			t.Fatal(err)
+		}
+
+		want := []string{
+			"nested post load: ",
+			"root post load: ",
+			"root validate: ",
+			"nested finalize: ",
+			"root finalize: ",
+		}
+		if !reflect.DeepEqual(calls, want) {
+			t.Errorf("wrong call order %v, want %v", calls, want)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		calls := make([]string, 0)
+		cfg := testHooks{calls: &calls, Nested: testHooksNested{calls: &calls}, err: errors.New("test")}
+
+		if err := ReadEnv(&cfg); err == nil || err.Error() != "test" {
+			t.Errorf("wrong error %v, want test", err)
+		}
+	})
+}
+
+func TestReadConfigExplicitZero(t *testing.T) {
+	type config struct {
+		Port    int               `yaml:"port" json:"port" toml:"port" env:"TEST_PORT" env-default:"8080"`
+		Enabled bool              `yaml:"enabled" json:"enabled" toml:"enabled" env:"TEST_ENABLED" env-default:"true"`
+		Name    string            `yaml:"name" json:"name" toml:"name" env:"TEST_NAME" env-default:"default"`
+		Hosts   []string          `yaml:"hosts" json:"hosts" toml:"hosts" env:"TEST_HOSTS" env-default:"a,b"`
+		Labels  map[string]string `yaml:"labels" json:"labels" toml:"labels" env:"TEST_LABELS" env-default:"a:b"`
+	}
+
+	tests := []struct {
+		name string
+		file string
+		ext  string
+		env  map[string]string
+		want *config
+	}{
+		{
+			name: "yaml explicit zero",
+			file: "port: 0\nenabled: false\nname: \"\"\nhosts: []\nlabels: {}\n",
+			ext:  "yaml",
+			want: &config{Hosts: []string{}, Labels: map[string]string{}},
+		},
+		{
+			name: "json explicit zero",
+			file: `{"port": 0, "enabled": false, "name": "", "hosts": [], "labels": {}}`,
+			ext:  "json",
+			want: &config{Hosts: []string{}, Labels: map[string]string{}},
+		},
+		{
+			name: "toml explicit zero",
+			file: "port = 0\nenabled = false\nname = \"\"\nhosts = []\n[labels]\n",
+			ext:  "toml",
+			want: &config{Hosts: []string{}, Labels: map[string]string{}},
+		},
+		{
+			name: "unset",
+			file: "other: 1\n",
+			ext:  "yaml",
+			want: &config{
+				Port:    8080,
+				Enabled: true,
+				Name:    "default",
+				Hosts:   []string{"a", "b"},
+				Labels:  map[string]string{"a": "b"},
+			},
+		},
+		{
+			name: "file map is not merged with default",
+			file: "labels:\n  c: d\n",
+			ext:  "yaml",
+			want: &config{
+				Port:    8080,
+				Enabled: true,
+				Name:    "default",
+				Hosts:   []string{"a", "b"},
+				Labels:  map[string]string{"c": "d"},
+			},
+		},
+		{
+			name: "env overrides explicit zero",
+			file: "port: 0\nenabled: false\n",
+			ext:  "yaml",
+			env:  map[string]string{"TEST_PORT": "9090"},
+			want: &config{
+				Port:   9090,
+				Name:   "default",
+				Hosts:  []string{"a", "b"},
+				Labels: map[string]string{"a": "b"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile, err := ioutil.TempFile(os.TempDir(), fmt.Sprintf("*.%s", tt.ext))
+			if err != nil {
+				t.Fatal("cannot create temporary file:", err)
+			}
+			defer os.Remove(tmpFile.Name())
+
+			if _, err = tmpFile.Write([]byte(tt.file)); err != nil {
+				t.Fatal("failed to write to temporary file:", err)
+			}
+
+			for env, val := range tt.env {
+				os.Setenv(env, val)
+			}
+			defer os.Clearenv()
+
+			var cfg config
+			if err = ReadConfig(tmpFile.Name(), &cfg); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(&cfg, tt.want) {
+				t.Errorf("wrong data %+v, want %+v", &cfg, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateEnvDefaults(t *testing.T) {
+	type config struct {
+		Level  string            `env:"TEST_LEVEL" env-upd:"" env-default:"info"`
+		Limit  int               `env:"TEST_LIMIT" env-upd:"" env-default:"10"`
+		Labels map[string]string `env:"TEST_LABELS" env-upd:"" env-default:"a:b"`
+		Host   string            `env:"TEST_HOST" env-default:"localhost"`
+	}
+
+	defer os.Clearenv()
+	os.Setenv("TEST_LIMIT", "20")
+
+	var cfg config
+	if err := UpdateEnv(&cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	// defaults are applied to updatable fields only
+	want := config{Level: "info", Limit: 20, Labels: map[string]string{"a": "b"}}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("wrong data %+v, want %+v", cfg, want)
+	}
+}
+
+func TestDuplicateEnv(t *testing.T) {
+	type database struct {
+		Timeout int `env:"TIMEOUT"`
+	}
+
+	type config struct {
+		Primary database `env-prefix:"TEST_DB_"`
+		Replica database `env-prefix:"TEST_DB_"`
+		Host    string   `env:"TEST_HOST,TEST_ADDR"`
+		Addr    string   `env:"TEST_ADDR"`
+	}
+
+	defer os.Clearenv()
+	os.Setenv("TEST_DB_TIMEOUT", "5")
+
+	want := `environment variable "TEST_ADDR" is used by fields "Host" and "Addr"; ` +
+		`environment variable "TEST_DB_TIMEOUT" is used by fields "Primary.Timeout" and "Replica.Timeout"`
+
+	var cfg config
+	if err := ReadEnv(&cfg); err == nil || err.Error() != want {
+		t.Errorf("wrong error %v, want %s", err, want)
+	}
+
+	if err := ReadEnv(&cfg, WithDuplicateEnv(CheckIgnore)); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Primary.Timeout != 5 || cfg.Replica.Timeout != 5 {
+		t.Errorf("wrong data %+v", cfg)
+	}
+}
@@ -0,0 +1,490 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/ilyakaznacheev/cleanenv/internal/suggest"
+)
+
+// cleanenvPath is the import path of the cleanenv package
+const cleanenvPath = "github.com/ilyakaznacheev/cleanenv"
+
+// envSkip is the env tag value that excludes the field from environment variables
+const envSkip = "-"
+
+// knownTags is the list of tags supported by cleanenv
+var knownTags = []string{
+	cleanenv.TagEnv,
+	cleanenv.TagEnvLayout,
+	cleanenv.TagEnvDefault,
+	cleanenv.TagEnvSeparator,
+	cleanenv.TagEnvDescription,
+	cleanenv.TagEnvUpd,
+	cleanenv.TagEnvRequired,
+	cleanenv.TagEnvPrefix,
+	cleanenv.TagEnvMin,
+	cleanenv.TagEnvMax,
+	cleanenv.TagEnvOneOf,
+	cleanenv.TagEnvPattern,
+	cleanenv.TagEnvLen,
+	cleanenv.TagEnvRequiredIf,
+	cleanenv.TagEnvRequiredWith,
+	cleanenv.TagEnvExclusiveGroup,
+	cleanenv.TagEnvSecret,
+	cleanenv.TagEnvDeprecated,
+}
+
+// Issue is a problem found in the configuration structure
+type Issue struct {
+	Pos     token.Position
+	Message string
+}
+
+// String returns the issue in the "file:line:column: message" format
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s", i.Pos, i.Message)
+}
+
+// linter checks configuration structures of a package
+type linter struct {
+	fset    *token.FileSet
+	issues  []Issue
+	checked map[types.Type]bool
+}
+
+// lintDir checks configuration structures of the package in the directory
+func lintDir(dir string) ([]Issue, error) {
+	fset := token.NewFileSet()
+
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &linter{
+		fset:    fset,
+		checked: make(map[types.Type]bool),
+	}
+
+	names := make([]string, 0, len(pkgs))
+	for name := range pkgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		files := make([]*ast.File, 0, len(pkgs[name].Files))
+		for _, f := range pkgs[name].Files {
+			files = append(files, f)
+		}
+		sort.Slice(files, func(i, j int) bool {
+			return fset.Position(files[i].Pos()).Filename < fset.Position(files[j].Pos()).Filename
+		})
+
+		l.lintFiles(dir, files)
+	}
+
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		a, b := l.issues[i].Pos, l.issues[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+
+	return l.issues, nil
+}
+
+// lintFiles type-checks the package files and checks the structures passed to cleanenv functions
+func (l *linter) lintFiles(dir string, files []*ast.File) {
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+
+	conf := types.Config{
+		Importer: importer.ForCompiler(l.fset, "source", nil),
+		// keep checking with incomplete type information, e.g. if some imports can't be found
+		Error: func(error) {},
+	}
+	path, _ := filepath.Abs(dir)
+	_, _ = conf.Check(path, l.fset, files, info)
+
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			fun := call.Fun
+			if index, ok := fun.(*ast.IndexExpr); ok {
+				// generic function instance, e.g. cleanenv.NewStore[Config]
+				fun = index.X
+				if isCleanenv(fun, info) {
+					l.lintType(info.TypeOf(index.Index))
+				}
+			}
+			if !isCleanenv(fun, info) {
+				return true
+			}
+
+			for _, arg := range call.Args {
+				l.lintType(info.TypeOf(arg))
+			}
+			return true
+		})
+	}
+}
+
+// isCleanenv determines if the expression is a function of the cleanenv package
+func isCleanenv(expr ast.Expr, info *types.Info) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	pkg, ok := info.Uses[ident].(*types.PkgName)
+	return ok && pkg.Imported().Path() == cleanenvPath
+}
+
+// lintType checks the configuration structure the type refers to
+func (l *linter) lintType(t types.Type) {
+	if t == nil {
+		return
+	}
+	if ptr, ok := t.Underlying().(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+
+	s, ok := t.Underlying().(*types.Struct)
+	if !ok || l.checked[t] {
+		return
+	}
+	l.checked[t] = true
+
+	l.lintStruct(s, "", "", make(map[string]string))
+}
+
+// lintStruct checks the fields of the structure.
+// The map envs holds the field paths by environment variable names to find duplicates.
+func (l *linter) lintStruct(s *types.Struct, path, prefix string, envs map[string]string) {
+	for i := 0; i < s.NumFields(); i++ {
+		field := s.Field(i)
+		tag := s.Tag(i)
+		pos := l.fset.Position(field.Pos())
+		fieldPath := path + field.Name()
+
+		report := func(format string, args ...interface{}) {
+			l.issues = append(l.issues, Issue{Pos: pos, Message: fmt.Sprintf(format, args...)})
+		}
+
+		for _, key := range tagKeys(tag) {
+			if !isEnvTag(key) || isKnownTag(key) {
+				continue
+			}
+			msg := fmt.Sprintf("unknown tag %q", key)
+			if known := suggest.Closest(key, knownTags); known != "" {
+				msg += fmt.Sprintf(", did you mean %q?", known)
+			}
+			report("%s", msg)
+		}
+
+		if !field.Exported() {
+			continue
+		}
+
+		st := reflect.StructTag(tag)
+
+		if isNestedStruct(field.Type()) {
+			nested, _ := st.Lookup(cleanenv.TagEnvPrefix)
+			l.lintStruct(field.Type().Underlying().(*types.Struct), fieldPath+".", prefix+nested, envs)
+			continue
+		}
+
+		env, hasEnv := st.Lookup(cleanenv.TagEnv)
+		if env == envSkip {
+			env, hasEnv = "", false
+		}
+		deprecated, hasDeprecated := st.Lookup(cleanenv.TagEnvDeprecated)
+		def, hasDefault := st.Lookup(cleanenv.TagEnvDefault)
+		if !hasEnv && !hasDefault && !hasDeprecated {
+			continue
+		}
+
+		if !supported(field.Type()) {
+			report("type %s of field %s is not supported", field.Type(), fieldPath)
+			continue
+		}
+
+		if hasDefault {
+			sep := ","
+			if value, ok := st.Lookup(cleanenv.TagEnvSeparator); ok {
+				sep = value
+			}
+			var layout *string
+			if value, ok := st.Lookup(cleanenv.TagEnvLayout); ok {
+				layout = &value
+			}
+
+			if err := checkValue(field.Type(), def, sep, layout); err != nil {
+				report("invalid %s value %q of field %s: %v", cleanenv.TagEnvDefault, def, fieldPath, err)
+			}
+		}
+
+		names := make([]string, 0)
+		if env != "" {
+			names = append(names, strings.Split(env, ",")...)
+		}
+		if deprecated != "" {
+			// deprecated names are still read, so they can't be used by other fields
+			for _, name := range strings.Split(deprecated, ",") {
+				names = append(names, strings.TrimSpace(name))
+			}
+		}
+		for _, name := range names {
+			name = prefix + name
+			if other, ok := envs[name]; ok && other != fieldPath {
+				report("environment variable %q of field %s is already used by field %s", name, fieldPath, other)
+				continue
+			}
+			envs[name] = fieldPath
+		}
+	}
+}
+
+// tagKeys returns the keys of the struct tag in order
+func tagKeys(tag string) []string {
+	keys := make([]string, 0)
+
+	for tag != "" {
+		// skip leading space
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		// scan to colon, a key is a non-empty string of non-control characters except space, quote and colon
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		keys = append(keys, tag[:i])
+		tag = tag[i+1:]
+
+		// scan quoted string to find the value end
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		tag = tag[i+1:]
+	}
+
+	return keys
+}
+
+// isEnvTag determines if the tag key looks like a cleanenv tag
+func isEnvTag(key string) bool {
+	key = strings.ToLower(key)
+	return key == "env" || strings.HasPrefix(key, "env-") || strings.HasPrefix(key, "env_")
+}
+
+// isKnownTag determines if the tag key is supported by cleanenv
+func isKnownTag(key string) bool {
+	for _, t := range knownTags {
+		if key == t {
+			return true
+		}
+	}
+	return false
+}
+
+// isNamed determines if the type is the named type from the package
+func isNamed(t types.Type, pkg, name string) bool {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == pkg && named.Obj().Name() == name
+}
+
+// isSpecialStruct determines if the type is a structure parsed from a string by cleanenv
+func isSpecialStruct(t types.Type) bool {
+	return isNamed(t, "time", "Time") || isNamed(t, "net/url", "URL")
+}
+
+// isNestedStruct determines if the type is a nested configuration structure
+func isNestedStruct(t types.Type) bool {
+	if _, ok := t.Underlying().(*types.Struct); !ok {
+		return false
+	}
+	return !isSpecialStruct(t)
+}
+
+// hasMethod determines if the type or the pointer to it has the method
+func hasMethod(t types.Type, name string) bool {
+	for _, typ := range []types.Type{t, types.NewPointer(t)} {
+		if sel := types.NewMethodSet(typ).Lookup(nil, name); sel != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// isCustom determines if the type parses its value itself (encoding.TextUnmarshaler or cleanenv.Setter)
+func isCustom(t types.Type) bool {
+	if _, ok := t.(*types.Pointer); ok {
+		return false
+	}
+	return hasMethod(t, "UnmarshalText") || hasMethod(t, "SetValue")
+}
+
+// isLocation determines if the type is *time.Location
+func isLocation(t types.Type) bool {
+	ptr, ok := t.(*types.Pointer)
+	return ok && isNamed(ptr.Elem(), "time", "Location")
+}
+
+// supported determines if the type can be parsed from an environment variable
+func supported(t types.Type) bool {
+	if isSpecialStruct(t) || isLocation(t) || isCustom(t) {
+		return true
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		return u.Info()&(types.IsBoolean|types.IsInteger|types.IsFloat|types.IsString) != 0 && u.Kind() != types.UntypedNil
+	case *types.Slice:
+		return supported(u.Elem())
+	case *types.Map:
+		return supported(u.Key()) && supported(u.Elem())
+	}
+	return false
+}
+
+// checkValue checks that the value can be parsed into the type the same way as cleanenv does
+func checkValue(t types.Type, value, sep string, layout *string) error {
+	switch {
+	case isNamed(t, "time", "Time"):
+		l := time.RFC3339
+		if layout != nil {
+			l = *layout
+		}
+		_, err := time.Parse(l, value)
+		return err
+	case isNamed(t, "net/url", "URL"):
+		_, err := url.Parse(value)
+		return err
+	case isLocation(t):
+		_, err := time.LoadLocation(value)
+		return err
+	case isNamed(t, "time", "Duration"):
+		_, err := time.ParseDuration(value)
+		return err
+	case isCustom(t):
+		// custom parsers can't be checked statically
+		return nil
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		return checkBasic(u, value)
+
+	case *types.Slice:
+		if b, ok := u.Elem().Underlying().(*types.Basic); ok && b.Kind() == types.Uint8 {
+			// byte slices are set from the raw value
+			return nil
+		}
+		if strings.TrimSpace(value) == "" {
+			return nil
+		}
+		for _, v := range strings.Split(value, sep) {
+			if err := checkValue(u.Elem(), v, sep, layout); err != nil {
+				return err
+			}
+		}
+
+	case *types.Map:
+		if strings.TrimSpace(value) == "" {
+			return nil
+		}
+		for _, pair := range strings.Split(value, sep) {
+			kv := strings.SplitN(pair, ":", 2)
+			if len(kv) != 2 {
+				return fmt.Errorf("invalid map item: %q", pair)
+			}
+			if err := checkValue(u.Key(), kv[0], sep, layout); err != nil {
+				return err
+			}
+			if err := checkValue(u.Elem(), kv[1], sep, layout); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkBasic checks that the value can be parsed into the basic type
+func checkBasic(b *types.Basic, value string) error {
+	var err error
+
+	switch b.Kind() {
+	case types.Bool:
+		_, err = strconv.ParseBool(value)
+	case types.Int, types.Int64:
+		_, err = strconv.ParseInt(value, 0, 64)
+	case types.Int8:
+		_, err = strconv.ParseInt(value, 0, 8)
+	case types.Int16:
+		_, err = strconv.ParseInt(value, 0, 16)
+	case types.Int32:
+		_, err = strconv.ParseInt(value, 0, 32)
+	case types.Uint, types.Uint64, types.Uintptr:
+		_, err = strconv.ParseUint(value, 0, 64)
+	case types.Uint8:
+		_, err = strconv.ParseUint(value, 0, 8)
+	case types.Uint16:
+		_, err = strconv.ParseUint(value, 0, 16)
+	case types.Uint32:
+		_, err = strconv.ParseUint(value, 0, 32)
+	case types.Float32:
+		_, err = strconv.ParseFloat(value, 32)
+	case types.Float64:
+		_, err = strconv.ParseFloat(value, 64)
+	}
+
+	return err
+}
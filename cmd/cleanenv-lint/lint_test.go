@@ -0,0 +1,60 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLintDir(t *testing.T) {
+	dir := filepath.Join("testdata", "config")
+
+	issues, err := lintDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(dir, "config.go")
+	want := []string{
+		file + `:10:2: unknown tag "env-requried", did you mean "env-required"?`,
+		file + `:11:2: invalid env-default value "port" of field Database.Port: strconv.ParseInt: parsing "port": invalid syntax`,
+		file + `:16:2: unknown tag "env_default", did you mean "env-default"?`,
+		file + `:18:2: type chan string of field Events is not supported`,
+		file + `:19:2: environment variable "DB_HOST" of field Address is already used by field Database.Host`,
+		file + `:20:2: environment variable "DB_PORT" of field Queue is already used by field Database.Port`,
+	}
+
+	if len(issues) != len(want) {
+		t.Fatalf("wrong issues %v, want %v", issues, want)
+	}
+	for i := range issues {
+		if got := issues[i].String(); got != want[i] {
+			t.Errorf("wrong issue %s, want %s", got, want[i])
+		}
+	}
+}
+
+func TestTagKeys(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want []string
+	}{
+		{tag: ``, want: []string{}},
+		{tag: `env:"HOST" env-default:"localhost"`, want: []string{"env", "env-default"}},
+		{tag: `yaml:"host"  env:"A\"B"`, want: []string{"yaml", "env"}},
+		{tag: `env:"HOST" broken`, want: []string{"env"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got := tagKeys(tt.tag)
+			if len(got) != len(tt.want) {
+				t.Fatalf("wrong keys %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("wrong keys %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Command cleanenv-lint checks tags of configuration structures used with cleanenv.
+//
+// It finds structures passed to cleanenv functions and reports:
+//
+//   - unknown tags with the "env" prefix, e.g. `env-requried` or `env_default`;
+//   - default values that can't be parsed into the field type;
+//   - field types that can't be read from environment variables;
+//   - environment variable names used by more than one field.
+//
+// Usage:
+//
+//	cleanenv-lint [packages]
+//
+// Packages are directories, a directory with "/..." suffix is checked recursively.
+// The current directory is checked if no packages are given.
+// The command exits with status 1 if any problems are found.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [packages]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	dirs, err := expandPatterns(patterns)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	var found bool
+	for _, dir := range dirs {
+		issues, err := lintDir(dir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		for _, issue := range issues {
+			fmt.Println(issue)
+			found = true
+		}
+	}
+
+	if found {
+		os.Exit(1)
+	}
+}
+
+// expandPatterns returns the list of package directories matching the patterns
+func expandPatterns(patterns []string) ([]string, error) {
+	dirs := make([]string, 0, len(patterns))
+
+	for _, p := range patterns {
+		if !strings.HasSuffix(p, "/...") {
+			dirs = append(dirs, p)
+			continue
+		}
+
+		root := strings.TrimSuffix(p, "/...")
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				return nil
+			}
+
+			name := info.Name()
+			if path != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+
+			if files, _ := filepath.Glob(filepath.Join(path, "*.go")); len(files) > 0 {
+				dirs = append(dirs, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return dirs, nil
+}
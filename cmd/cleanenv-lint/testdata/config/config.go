@@ -0,0 +1,30 @@
+package config
+
+import (
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+type database struct {
+	Host string `env:"HOST" env-requried:""`
+	Port int    `env:"PORT" env-default:"port"`
+}
+
+type config struct {
+	Database database      `env-prefix:"DB_"`
+	Timeout  time.Duration `env:"TIMEOUT" env_default:"5s"`
+	Started  time.Time     `env:"STARTED" env-default:"2020-01-02" env-layout:"2006-01-02"`
+	Events   chan string   `env:"EVENTS"`
+	Address  string        `env:"DB_HOST"`
+	Queue    string        `env:"QUEUE" env-deprecated:"DB_PORT"`
+	Ignored  chan string
+	Token    string `env:"-"`
+	Secret   string `env:"-"`
+}
+
+func read() (config, error) {
+	var cfg config
+	err := cleanenv.ReadEnv(&cfg)
+	return cfg, err
+}
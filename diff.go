@@ -0,0 +1,211 @@
+package cleanenv
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// redactedValue replaces values of secret fields in the output
+const redactedValue = "******"
+
+// Change is a difference of a configuration field value between two configurations
+type Change struct {
+	// Path is the field path, e.g. "Database.Host"
+	Path string
+	// Env is the list of environment variable names of the field
+	Env []string
+	// Old is the previous field value
+	Old interface{}
+	// New is the current field value
+	New interface{}
+}
+
+// Diff returns the list of fields that have different values in two configurations.
+// Both configurations must be pointers to structures of the same type, otherwise Diff returns nil.
+//
+// Values of secret fields (marked with `env-secret` tag) are redacted.
+//
+// Example:
+//
+//	old := cfg
+//	if err := cleanenv.UpdateEnv(&cfg); err != nil {
+//		...
+//	}
+//	for _, c := range cleanenv.Diff(&old, &cfg) {
+//		log.Printf("%s changed from %v to %v", c.Path, c.Old, c.New)
+//	}
+func Diff(old, new interface{}) []Change {
+	changes, err := diff(old, new, true)
+	if err != nil {
+		return nil
+	}
+	return changes
+}
+
+// diff compares two configurations field by field
+func diff(old, new interface{}, redact bool) ([]Change, error) {
+	if reflect.TypeOf(old) != reflect.TypeOf(new) {
+		return nil, fmt.Errorf("different types %T and %T", old, new)
+	}
+
+	if _, err := cfgStruct(old); err != nil {
+		return nil, err
+	}
+
+	oldMeta, err := readStructMetadata(old)
+	if err != nil {
+		return nil, err
+	}
+
+	newMeta, err := readStructMetadata(new)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]Change, 0)
+
+	for i := range oldMeta {
+		oldValue, newValue := oldMeta[i].fieldValue.Interface(), newMeta[i].fieldValue.Interface()
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+
+		if redact && oldMeta[i].secret {
+			oldValue, newValue = redactedValue, redactedValue
+		}
+
+		changes = append(changes, Change{
+			Path: oldMeta[i].path + oldMeta[i].fieldName,
+			Env:  oldMeta[i].envList,
+			Old:  oldValue,
+			New:  newValue,
+		})
+	}
+
+	return changes, nil
+}
+
+// changeHandler is a function registered by OnChange
+type changeHandler struct {
+	id   int
+	path string
+	fn   func(old, new interface{})
+}
+
+// changeHandlers is a registry of change handlers by configuration
+var changeHandlers = struct {
+	sync.Mutex
+	byConfig map[interface{}][]changeHandler
+	nextID   int
+}{
+	byConfig: make(map[interface{}][]changeHandler),
+}
+
+// OnChange registers a function called when the value of an updatable field (marked with `env-upd` tag) changes
+// during UpdateEnv, Watch or ReloadOnSignal. The field is identified by its path, e.g. "Log.Level".
+// The function receives the previous and the new field values, secret fields are not redacted.
+//
+// It returns a function to cancel the subscription. The configuration is referenced until the subscription is cancelled.
+//
+// Example:
+//
+//	cancel, err := cleanenv.OnChange(&cfg, "Log.Level", func(old, new interface{}) {
+//		logger.SetLevel(new.(string))
+//	})
+func OnChange(cfg interface{}, path string, fn func(old, new interface{})) (func(), error) {
+	if _, err := cfgStruct(cfg); err != nil {
+		return nil, err
+	}
+
+	metaInfo, err := readStructMetadata(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta *structMeta
+	for i := range metaInfo {
+		if metaInfo[i].path+metaInfo[i].fieldName == path {
+			meta = &metaInfo[i]
+			break
+		}
+	}
+	if meta == nil {
+		return nil, fmt.Errorf("field %q not found", path)
+	}
+	if !meta.updatable {
+		return nil, fmt.Errorf("field %q is not updatable", path)
+	}
+
+	changeHandlers.Lock()
+	defer changeHandlers.Unlock()
+
+	id := changeHandlers.nextID
+	changeHandlers.nextID++
+	changeHandlers.byConfig[cfg] = append(changeHandlers.byConfig[cfg], changeHandler{id, path, fn})
+
+	return func() {
+		changeHandlers.Lock()
+		defer changeHandlers.Unlock()
+
+		handlers := changeHandlers.byConfig[cfg]
+		for i, h := range handlers {
+			if h.id == id {
+				handlers = append(handlers[:i:i], handlers[i+1:]...)
+				break
+			}
+		}
+		if len(handlers) == 0 {
+			delete(changeHandlers.byConfig, cfg)
+		} else {
+			changeHandlers.byConfig[cfg] = handlers
+		}
+	}, nil
+}
+
+// changeSnapshot returns a copy of the configuration if there are change handlers registered for it, otherwise nil
+func changeSnapshot(cfg interface{}) interface{} {
+	// only pointers are registered, other values may be not comparable
+	s, err := cfgStruct(cfg)
+	if err != nil {
+		return nil
+	}
+
+	changeHandlers.Lock()
+	_, ok := changeHandlers.byConfig[cfg]
+	changeHandlers.Unlock()
+	if !ok {
+		return nil
+	}
+
+	old := reflect.New(s.Type())
+	old.Elem().Set(s)
+	return old.Interface()
+}
+
+// notifyChanges calls change handlers registered for the changed fields of the configuration
+func notifyChanges(cfg, old interface{}) {
+	if old == nil {
+		return
+	}
+
+	changeHandlers.Lock()
+	handlers := append([]changeHandler(nil), changeHandlers.byConfig[cfg]...)
+	changeHandlers.Unlock()
+	if len(handlers) == 0 {
+		return
+	}
+
+	changes, err := diff(old, cfg, false)
+	if err != nil {
+		return
+	}
+
+	for _, c := range changes {
+		for _, h := range handlers {
+			if h.path == c.Path {
+				h.fn(c.Old, c.New)
+			}
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package cleanenv
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	type database struct {
+		Host     string `env:"HOST"`
+		Password string `env:"PASSWORD" env-secret:""`
+	}
+
+	type config struct {
+		Database database `env-prefix:"DB_"`
+		Level    string   `env:"LEVEL,LOG_LEVEL"`
+		Timeout  time.Duration
+		Hosts    []string `env:"HOSTS"`
+	}
+
+	base := config{
+		Database: database{Host: "localhost", Password: "secret"},
+		Level:    "info",
+		Timeout:  time.Second,
+		Hosts:    []string{"a", "b"},
+	}
+
+	tests := []struct {
+		name string
+		old  interface{}
+		new  interface{}
+		want []Change
+	}{
+		{
+			name: "equal",
+			old:  &base,
+			new:  &config{Database: base.Database, Level: "info", Timeout: time.Second, Hosts: []string{"a", "b"}},
+			want: []Change{},
+		},
+		{
+			name: "changed",
+			old:  &base,
+			new: &config{
+				Database: database{Host: "remote", Password: "new secret"},
+				Level:    "debug",
+				Timeout:  time.Second,
+				Hosts:    []string{"a"},
+			},
+			want: []Change{
+				{Path: "Level", Env: []string{"LEVEL", "LOG_LEVEL"}, Old: "info", New: "debug"},
+				{Path: "Hosts", Env: []string{"HOSTS"}, Old: []string{"a", "b"}, New: []string{"a"}},
+				{Path: "Database.Host", Env: []string{"DB_HOST"}, Old: "localhost", New: "remote"},
+				{Path: "Database.Password", Env: []string{"DB_PASSWORD"}, Old: "******", New: "******"},
+			},
+		},
+		{
+			name: "different types",
+			old:  &base,
+			new:  &database{},
+			want: nil,
+		},
+		{
+			name: "not a structure",
+			old:  42,
+			new:  43,
+			want: nil,
+		},
+		{
+			name: "structure values",
+			old:  base,
+			new:  config{Level: "debug"},
+			want: nil,
+		},
+		{
+			name: "nil pointers",
+			old:  (*config)(nil),
+			new:  (*config)(nil),
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(tt.old, tt.new)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("wrong changes %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOnChange(t *testing.T) {
+	type logging struct {
+		Level string `env:"LEVEL" env-upd:""`
+	}
+
+	type config struct {
+		Log   logging `env-prefix:"TEST_LOG_"`
+		Limit int     `env:"TEST_LIMIT" env-upd:""`
+		Host  string  `env:"TEST_HOST"`
+	}
+
+	defer os.Clearenv()
+	os.Setenv("TEST_LOG_LEVEL", "info")
+	os.Setenv("TEST_LIMIT", "10")
+
+	var cfg config
+	if err := ReadEnv(&cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	var levels [][2]interface{}
+	cancel, err := OnChange(&cfg, "Log.Level", func(old, new interface{}) {
+		levels = append(levels, [2]interface{}{old, new})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("changed", func(t *testing.T) {
+		os.Setenv("TEST_LOG_LEVEL", "debug")
+		if err := UpdateEnv(&cfg); err != nil {
+			t.Fatal(err)
+		}
+		want := [][2]interface{}{{"info", "debug"}}
+		if !reflect.DeepEqual(levels, want) {
+			t.Errorf("wrong changes %v, want %v", levels, want)
+		}
+	})
+
+	t.Run("other field changed", func(t *testing.T) {
+		os.Setenv("TEST_LIMIT", "20")
+		if err := UpdateEnv(&cfg); err != nil {
+			t.Fatal(err)
+		}
+		if len(levels) != 1 {
+			t.Errorf("unexpected changes %v", levels)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		cancel()
+		os.Setenv("TEST_LOG_LEVEL", "warn")
+		if err := UpdateEnv(&cfg); err != nil {
+			t.Fatal(err)
+		}
+		if len(levels) != 1 {
+			t.Errorf("unexpected changes %v", levels)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		fn := func(old, new interface{}) {}
+		if _, err := OnChange(&cfg, "Log.Unknown", fn); err == nil || err.Error() != `field "Log.Unknown" not found` {
+			t.Errorf("wrong error %v", err)
+		}
+		if _, err := OnChange(&cfg, "Host", fn); err == nil || err.Error() != `field "Host" is not updatable` {
+			t.Errorf("wrong error %v", err)
+		}
+		if _, err := OnChange(cfg, "Limit", fn); err == nil {
+			t.Error("expected error for non-pointer config")
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package cleanenv
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ilyakaznacheev/cleanenv/internal/suggest"
+)
+
+// WithUnknownEnv sets how environment variables that start with one of the configured prefixes (`env-prefix` tag)
+// but don't match any field are reported. By default, such variables are ignored.
+//
+// Every unknown variable is reported with a suggestion of the intended variable name if there is a similar one.
+//
+// Example:
+//
+//	err := cleanenv.ReadEnv(&cfg, cleanenv.WithUnknownEnv(cleanenv.CheckWarn))
+//	// environment variable "APP_DATBASE_HOST" doesn't match any field, did you mean "APP_DATABASE_HOST"?
+func WithUnknownEnv(mode CheckMode) Option {
+	return func(o *options) {
+		o.unknownEnv = mode
+	}
+}
+
+// WithNormalizedEnv makes the lookup of environment variables case-insensitive, "-" and "." in names are treated as "_".
+// E.g. the variable "app-db-host" is read into the field with `env:"APP_DB_HOST"` tag.
+//
+// If several environment variables have the same normalized name as a field variable, e.g. "APP_DB_HOST" and "app-db-host",
+// reading fails because the value is ambiguous.
+func WithNormalizedEnv() Option {
+	return func(o *options) {
+		o.normalizeEnv = true
+	}
+}
+
+// envNormalizer replaces the characters treated as "_" by WithNormalizedEnv
+var envNormalizer = strings.NewReplacer("-", "_", ".", "_")
+
+// normalizeEnv returns the normalized environment variable name
+func normalizeEnv(name string) string {
+	return strings.ToUpper(envNormalizer.Replace(name))
+}
+
+// envKey returns the name used to match environment variables, it is normalized if WithNormalizedEnv is set
+func (o *options) envKey(name string) string {
+	if o.normalizeEnv {
+		return normalizeEnv(name)
+	}
+	return name
+}
+
+// lookupFunc returns the actual name and the value of the environment variable matching the field variable name
+type lookupFunc func(name string) (key, value string, ok bool, err error)
+
+// envLookup returns the function to look up environment variables according to the options
+func (o *options) envLookup() lookupFunc {
+	if !o.normalizeEnv {
+		return func(name string) (string, string, bool, error) {
+			value, ok := os.LookupEnv(name)
+			return name, value, ok, nil
+		}
+	}
+
+	environ := os.Environ()
+	sort.Strings(environ)
+
+	// actual variable names by normalized name
+	vars := make(map[string][]string)
+	for _, kv := range environ {
+		env := strings.SplitN(kv, "=", 2)[0]
+		vars[normalizeEnv(env)] = append(vars[normalizeEnv(env)], env)
+	}
+
+	return func(name string) (string, string, bool, error) {
+		envs := vars[normalizeEnv(name)]
+		if len(envs) > 1 {
+			quoted := make([]string, len(envs))
+			for i := range envs {
+				quoted[i] = strconv.Quote(envs[i])
+			}
+			return "", "", false, fmt.Errorf("environment variables %s have the same normalized name %q",
+				strings.Join(quoted, ", "), normalizeEnv(name),
+			)
+		}
+		if len(envs) == 0 {
+			return "", "", false, nil
+		}
+
+		value, ok := os.LookupEnv(envs[0])
+		return envs[0], value, ok, nil
+	}
+}
+
+// checkEnv reports environment variables with configured prefixes that don't match any field
+func (o *options) checkEnv(nodes []cfgNode, metaInfo []structMeta) error {
+	if o.unknownEnv == CheckIgnore {
+		return nil
+	}
+
+	prefixes := make([]string, 0)
+	for _, n := range nodes {
+		if n.Prefix != "" {
+			prefixes = append(prefixes, o.envKey(n.Prefix))
+		}
+	}
+	if len(prefixes) == 0 {
+		return nil
+	}
+
+	known := make(map[string]bool)
+	names := make([]string, 0)
+	for _, meta := range metaInfo {
+		for _, env := range meta.envNames() {
+			if !known[o.envKey(env)] {
+				known[o.envKey(env)] = true
+				names = append(names, env)
+			}
+		}
+	}
+
+	environ := os.Environ()
+	sort.Strings(environ)
+
+	problems := make([]string, 0)
+	for _, kv := range environ {
+		env := strings.SplitN(kv, "=", 2)[0]
+		if known[o.envKey(env)] || !hasAnyPrefix(o.envKey(env), prefixes) {
+			continue
+		}
+
+		problem := fmt.Sprintf("environment variable %q doesn't match any field", env)
+		if s := suggest.Closest(env, names); s != "" {
+			problem += fmt.Sprintf(", did you mean %q?", s)
+		}
+		problems = append(problems, problem)
+	}
+
+	return o.check(o.unknownEnv, problems)
+}
+
+// warnDeprecated reports the use of the deprecated environment variable with the name to migrate to
+func (o *options) warnDeprecated(env, replacement string) {
+	if replacement == "" {
+		o.logger.Printf("cleanenv: environment variable %q is deprecated", env)
+		return
+	}
+	o.logger.Printf("cleanenv: environment variable %q is deprecated, use %q instead", env, replacement)
+}
+
+// hasAnyPrefix determines if the string starts with one of the prefixes
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+package cleanenv
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"testing"
+)
+
+func TestUnknownEnv(t *testing.T) {
+	type database struct {
+		Host string `env:"HOST"`
+		Port int    `env:"PORT"`
+	}
+
+	type config struct {
+		Database database `env-prefix:"TEST_APP_DATABASE_"`
+		Name     string   `env:"TEST_APP_NAME"`
+		Level    string   `env:"LEVEL"`
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{
+			name: "known variables",
+			env:  map[string]string{"TEST_APP_DATABASE_HOST": "localhost", "TEST_APP_NAME": "app", "LEVEL": "info"},
+		},
+		{
+			name: "misspelled variable",
+			env:  map[string]string{"TEST_APP_DATBASE_HOST": "localhost"},
+		},
+		{
+			name: "misspelled prefixed variable",
+			env:  map[string]string{"TEST_APP_DATABASE_PROT": "5432", "TEST_APP_DATABASE_TIMEOUT": "1s"},
+			want: `environment variable "TEST_APP_DATABASE_PROT" doesn't match any field, did you mean "TEST_APP_DATABASE_PORT"?; ` +
+				`environment variable "TEST_APP_DATABASE_TIMEOUT" doesn't match any field`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer os.Clearenv()
+			for env, val := range tt.env {
+				os.Setenv(env, val)
+			}
+
+			var cfg config
+			if err := ReadEnv(&cfg); err != nil {
+				t.Fatal("unexpected error with default options:", err)
+			}
+
+			err := ReadEnv(&cfg, WithUnknownEnv(CheckError))
+			if (err != nil) != (tt.want != "") {
+				t.Fatalf("wrong error behavior %v, want %q", err, tt.want)
+			}
+			if err != nil && err.Error() != tt.want {
+				t.Errorf("wrong error\n%v\nwant\n%s", err, tt.want)
+			}
+
+			var buf bytes.Buffer
+			if err = UpdateEnv(&cfg, WithUnknownEnv(CheckWarn), WithLogger(log.New(&buf, "", 0))); err != nil {
+				t.Fatal("unexpected error in warning mode:", err)
+			}
+			if (buf.Len() != 0) != (tt.want != "") {
+				t.Errorf("wrong warnings %q", buf.String())
+			}
+		})
+	}
+}
+
+func TestNormalizedEnv(t *testing.T) {
+	type database struct {
+		Host string `env:"HOST"`
+		Port int    `env:"PORT"`
+	}
+
+	type config struct {
+		Database database `env-prefix:"APP_DB_"`
+		Level    string   `env:"APP_LEVEL"`
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		opts    []Option
+		want    config
+		wantErr string
+	}{
+		{
+			name: "normalized",
+			env:  map[string]string{"app-db-host": "localhost", "App.Db.Port": "5432", "APP_LEVEL": "debug"},
+			opts: []Option{WithNormalizedEnv()},
+			want: config{Database: database{Host: "localhost", Port: 5432}, Level: "debug"},
+		},
+		{
+			name: "disabled",
+			env:  map[string]string{"app-db-host": "localhost", "APP_LEVEL": "debug"},
+			want: config{Level: "debug"},
+		},
+		{
+			name:    "collision",
+			env:     map[string]string{"app-db-host": "localhost", "APP_DB_HOST": "remote"},
+			opts:    []Option{WithNormalizedEnv()},
+			wantErr: `reading field "Database.Host": environment variables "APP_DB_HOST", "app-db-host" have the same normalized name "APP_DB_HOST"`,
+		},
+		{
+			name:    "unknown",
+			env:     map[string]string{"app-db-host": "localhost", "app-db-prot": "5432"},
+			opts:    []Option{WithNormalizedEnv(), WithUnknownEnv(CheckError)},
+			wantErr: `environment variable "app-db-prot" doesn't match any field, did you mean "APP_DB_PORT"?`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Clearenv()
+			defer os.Clearenv()
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+			}
+
+			var cfg config
+			err := ReadEnv(&cfg, tt.opts...)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("wrong error %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg != tt.want {
+				t.Errorf("wrong data %+v, want %+v", cfg, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeprecatedEnv(t *testing.T) {
+	type database struct {
+		Host string `env:"HOST" env-deprecated:"ADDR,SERVER"`
+	}
+
+	type config struct {
+		Database database `env-prefix:"DATABASE_"`
+		Level    string   `env-deprecated:"LOG_LEVEL"`
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    config
+		wantLog string
+	}{
+		{
+			name: "current name",
+			env:  map[string]string{"DATABASE_HOST": "current", "DATABASE_ADDR": "deprecated"},
+			want: config{Database: database{Host: "current"}},
+		},
+		{
+			name:    "deprecated name",
+			env:     map[string]string{"DATABASE_SERVER": "deprecated"},
+			want:    config{Database: database{Host: "deprecated"}},
+			wantLog: "cleanenv: environment variable \"DATABASE_SERVER\" is deprecated, use \"DATABASE_HOST\" instead\n",
+		},
+		{
+			name:    "no replacement",
+			env:     map[string]string{"LOG_LEVEL": "debug"},
+			want:    config{Level: "debug"},
+			wantLog: "cleanenv: environment variable \"LOG_LEVEL\" is deprecated\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Clearenv()
+			defer os.Clearenv()
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+			}
+
+			var buf bytes.Buffer
+			var cfg config
+			if err := ReadEnv(&cfg, WithLogger(log.New(&buf, "", 0))); err != nil {
+				t.Fatal(err)
+			}
+			if cfg != tt.want {
+				t.Errorf("wrong data %+v, want %+v", cfg, tt.want)
+			}
+			if buf.String() != tt.wantLog {
+				t.Errorf("wrong log %q, want %q", buf.String(), tt.wantLog)
+			}
+		})
+	}
+
+	t.Run("description", func(t *testing.T) {
+		want := "Environment variables:" +
+			"\n\nDatabase (DATABASE_):" +
+			"\n  DATABASE_HOST string\n    \tdatabase host" +
+			"\n  DATABASE_ADDR string (deprecated, use DATABASE_HOST)\n    \tdatabase host" +
+			"\n  DATABASE_SERVER string (deprecated, use DATABASE_HOST)\n    \tdatabase host"
+
+		type described struct {
+			Database struct {
+				Host string `env:"HOST" env-deprecated:"ADDR,SERVER" env-description:"database host"`
+			} `env-prefix:"DATABASE_"`
+		}
+
+		got, err := GetDescription(&described{}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("wrong description text %s, want %s", got, want)
+		}
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		type duplicate struct {
+			Host    string `env:"HOST"`
+			Address string `env:"ADDRESS" env-deprecated:"HOST"`
+		}
+
+		want := `environment variable "HOST" is used by fields "Host" and "Address"`
+		if err := ReadEnv(&duplicate{}); err == nil || err.Error() != want {
+			t.Errorf("wrong error %v, want %s", err, want)
+		}
+	})
+}
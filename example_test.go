@@ -28,10 +28,10 @@ func ExampleGetDescription() {
 	//Output: Environment variables:
 	//   ONE int64
 	//     	first parameter
-	//   THREE string
-	//     	third parameter
 	//   TWO float64
 	//     	second parameter
+	//   THREE string
+	//     	third parameter
 }
 
 // ExampleGetDescription_defaults builds a description text from structure tags with description of default values
@@ -53,10 +53,10 @@ func ExampleGetDescription_defaults() {
 	//Output: Environment variables:
 	//   ONE int64
 	//     	first parameter (default "1")
-	//   THREE string
-	//     	third parameter (default "test")
 	//   TWO float64
 	//     	second parameter (default "2.2")
+	//   THREE string
+	//     	third parameter (default "test")
 }
 
 // ExampleGetDescription_variableList builds a description text from structure tags with description of alternative variables
@@ -76,10 +76,10 @@ func ExampleGetDescription_variableList() {
 	//Output: Environment variables:
 	//   ONE int64
 	//     	first found parameter
-	//   THREE int64 (alternative to ONE)
-	//     	first found parameter
 	//   TWO int64 (alternative to ONE)
 	//     	first found parameter
+	//   THREE int64 (alternative to ONE)
+	//     	first found parameter
 }
 
 // ExampleGetDescription_customHeaderText builds a description text from structure tags with custom header string
@@ -103,10 +103,10 @@ func ExampleGetDescription_customHeaderText() {
 	//Output: Custom header text:
 	//   ONE int64
 	//     	first parameter
-	//   THREE string
-	//     	third parameter
 	//   TWO float64
 	//     	second parameter
+	//   THREE string
+	//     	third parameter
 }
 
 // ExampleUpdateEnv updates variables in the configuration structure.
@@ -163,8 +163,8 @@ func ExampleReadEnv() {
 	//Output: {Port:5050 Host:localhost Name:redis User:tester Password:*****}
 }
 
-// ExampleReadEnv reads environment variables or default values into the structure
-func ExampleReadEnvWithURL() {
+// ExampleReadEnv_withURL reads environment variables into the structure with a URL field
+func ExampleReadEnv_withURL() {
 	type config struct {
 		ImageCDN url.URL `env:"IMAGE_CDN"`
 	}
@@ -279,8 +279,8 @@ func ExampleUsage() {
 	// My sweet variables:
 	//   ONE int64
 	//     	first parameter
-	//   THREE string
-	//     	third parameter
 	//   TWO float64
 	//     	second parameter
+	//   THREE string
+	//     	third parameter
 }
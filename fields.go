@@ -0,0 +1,124 @@
+package cleanenv
+
+import (
+	"reflect"
+)
+
+// FieldInfo describes a configuration field
+type FieldInfo struct {
+	// Path is the field path, e.g. "Database.Host"
+	Path string `json:"path"`
+	// Name is the field name
+	Name string `json:"name"`
+	// Type is the Go type name of the field, e.g. "time.Duration"
+	Type string `json:"type"`
+	// Tag is the field tag, it can be used to read tags of other libraries
+	Tag reflect.StructTag `json:"-"`
+	// Prefix is the environment variable prefix of the structure the field belongs to
+	Prefix string `json:"prefix,omitempty"`
+	// Env is the list of environment variable names of the field
+	Env []string `json:"env,omitempty"`
+	// Deprecated is the list of deprecated environment variable names, they are read if none of Env is set
+	Deprecated []string `json:"deprecated,omitempty"`
+	// Default is the default value of the field, it is nil if the default value is not set
+	Default *string `json:"default,omitempty"`
+	// Separator is the separator of list and map items
+	Separator string `json:"separator,omitempty"`
+	// Layout is the time layout of time.Time fields, it is nil if the layout is not set
+	Layout *string `json:"layout,omitempty"`
+	// Description is the field description
+	Description string `json:"description,omitempty"`
+	// Required shows that the field value must be provided
+	Required bool `json:"required,omitempty"`
+	// Updatable shows that the field is updated by UpdateEnv
+	Updatable bool `json:"updatable,omitempty"`
+	// Secret shows that the field value must not be printed
+	Secret bool `json:"secret,omitempty"`
+	// Value is the current field value, it is redacted for secret fields
+	Value interface{} `json:"value"`
+
+	// Min is the minimal value or length of the field, it is nil if not set
+	Min *string `json:"min,omitempty"`
+	// Max is the maximal value or length of the field, it is nil if not set
+	Max *string `json:"max,omitempty"`
+	// Len is the exact length of the field, it is nil if not set
+	Len *string `json:"len,omitempty"`
+	// Pattern is the regular expression the field value must match, it is nil if not set
+	Pattern *string `json:"pattern,omitempty"`
+	// OneOf is the list of allowed field values
+	OneOf []string `json:"oneOf,omitempty"`
+
+	// RequiredIf is the condition in form "Field=value" making the field required, it is nil if not set
+	RequiredIf *string `json:"requiredIf,omitempty"`
+	// RequiredWith is the list of fields making the field required if any of them is set
+	RequiredWith []string `json:"requiredWith,omitempty"`
+	// ExclusiveGroup is the name of the group of mutually exclusive fields
+	ExclusiveGroup string `json:"exclusiveGroup,omitempty"`
+}
+
+// Fields returns the metadata of all configuration fields, including the fields of nested structures.
+// Fields of a structure are listed in the order of declaration, nested structures are listed after their parents.
+//
+// The configuration can be a structure or a pointer to a structure.
+// Pass the same options as for reading, e.g. WithAutoEnv, to get the same environment variable names.
+//
+// Example:
+//
+//	fields, err := cleanenv.Fields(&cfg)
+//	if err != nil {
+//		...
+//	}
+//	for _, f := range fields {
+//		fmt.Println(f.Path, f.Type, f.Env)
+//	}
+func Fields(cfg interface{}, opts ...Option) ([]FieldInfo, error) {
+	// copy structure values to make the fields addressable
+	if v := reflect.ValueOf(cfg); v.Kind() == reflect.Struct {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		cfg = ptr.Interface()
+	}
+
+	_, metaInfo, err := readStructTree(cfg, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]FieldInfo, 0, len(metaInfo))
+	for i := range metaInfo {
+		fields = append(fields, metaInfo[i].fieldInfo())
+	}
+
+	return fields, nil
+}
+
+// fieldInfo returns the exported field metadata
+func (sm *structMeta) fieldInfo() FieldInfo {
+	return FieldInfo{
+		Path:        sm.path + sm.fieldName,
+		Name:        sm.fieldName,
+		Type:        sm.fieldValue.Type().String(),
+		Tag:         sm.fieldTag,
+		Prefix:      sm.prefix,
+		Env:         sm.envList,
+		Deprecated:  sm.deprecated,
+		Default:     sm.defValue,
+		Separator:   sm.separator,
+		Layout:      sm.layout,
+		Description: sm.description,
+		Required:    sm.required,
+		Updatable:   sm.updatable,
+		Secret:      sm.secret,
+		Value:       sm.reportValue(),
+
+		Min:     sm.min,
+		Max:     sm.max,
+		Len:     sm.length,
+		Pattern: sm.pattern,
+		OneOf:   sm.oneOf,
+
+		RequiredIf:     sm.requiredIf,
+		RequiredWith:   sm.requiredWith,
+		ExclusiveGroup: sm.exclusiveGroup,
+	}
+}
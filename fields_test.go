@@ -0,0 +1,85 @@
+package cleanenv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFields(t *testing.T) {
+	type database struct {
+		Host     string `yaml:"host" env:"HOST" env-default:"localhost" env-description:"Database host"`
+		Password string `env:"PASSWORD" env-secret:"" env-required-with:"Host"`
+	}
+
+	type config struct {
+		Database database      `env-prefix:"DB_"`
+		Timeout  time.Duration `env:"TIMEOUT,TTL" env-min:"1s" env-upd:""`
+		Level    string        `env:"LEVEL" env-oneof:"debug,info" env-required:""`
+		Hosts    []string      `env:"HOSTS" env-separator:";"`
+		Started  time.Time     `env-layout:"2006-01-02"`
+	}
+
+	def := "localhost"
+	min := "1s"
+	layout := "2006-01-02"
+
+	cfg := config{
+		Database: database{Host: "db", Password: "secret"},
+		Timeout:  time.Second,
+	}
+
+	want := []FieldInfo{
+		{
+			Path: "Timeout", Name: "Timeout", Type: "time.Duration", Tag: `env:"TIMEOUT,TTL" env-min:"1s" env-upd:""`,
+			Env: []string{"TIMEOUT", "TTL"}, Separator: ",", Updatable: true, Value: time.Second, Min: &min,
+		},
+		{
+			Path: "Level", Name: "Level", Type: "string", Tag: `env:"LEVEL" env-oneof:"debug,info" env-required:""`,
+			Env: []string{"LEVEL"}, Separator: ",", Required: true, Value: "", OneOf: []string{"debug", "info"},
+		},
+		{
+			Path: "Hosts", Name: "Hosts", Type: "[]string", Tag: `env:"HOSTS" env-separator:";"`,
+			Env: []string{"HOSTS"}, Separator: ";", Value: []string(nil),
+		},
+		{
+			Path: "Started", Name: "Started", Type: "time.Time", Tag: `env-layout:"2006-01-02"`,
+			Env: []string{}, Separator: ",", Layout: &layout, Value: time.Time{},
+		},
+		{
+			Path: "Database.Host", Name: "Host", Type: "string",
+			Tag:    `yaml:"host" env:"HOST" env-default:"localhost" env-description:"Database host"`,
+			Prefix: "DB_", Env: []string{"DB_HOST"}, Default: &def, Separator: ",", Description: "Database host", Value: "db",
+		},
+		{
+			Path: "Database.Password", Name: "Password", Type: "string", Tag: `env:"PASSWORD" env-secret:"" env-required-with:"Host"`,
+			Prefix: "DB_", Env: []string{"DB_PASSWORD"}, Separator: ",", Secret: true, Value: "******", RequiredWith: []string{"Host"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		cfg  interface{}
+	}{
+		{name: "pointer", cfg: &cfg},
+		{name: "structure", cfg: cfg},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Fields(tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("wrong fields\n%+v\nwant\n%+v", got, want)
+			}
+		})
+	}
+
+	t.Run("not a structure", func(t *testing.T) {
+		if _, err := Fields(42); err == nil {
+			t.Error("expected error but got nil")
+		}
+	})
+}
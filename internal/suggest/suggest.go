@@ -0,0 +1,86 @@
+// Package suggest finds the closest match of a misspelled name.
+// It is shared by the cleanenv package and the cleanenv-lint tool.
+package suggest
+
+import (
+	"strings"
+)
+
+// Closest returns the candidate closest to the name, or an empty string if there are no similar candidates.
+// The names are compared case-insensitively.
+func Closest(name string, candidates []string) string {
+	name = strings.ToLower(name)
+
+	var (
+		best         string
+		bestDistance int
+	)
+	for _, c := range candidates {
+		d := editDistance(name, strings.ToLower(c))
+
+		// allow one typo per four characters after the common prefix, but at least one
+		rest := len([]rune(name)) - commonPrefix(name, strings.ToLower(c))
+		if d > 1+rest/4 {
+			continue
+		}
+
+		if best == "" || d < bestDistance {
+			best, bestDistance = c, d
+		}
+	}
+
+	return best
+}
+
+// commonPrefix returns the length of the common prefix of two strings in runes
+func commonPrefix(a, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	n := 0
+	for n < len(s) && n < len(t) && s[n] == t[n] {
+		n++
+	}
+	return n
+}
+
+// editDistance returns the Levenshtein distance between two strings,
+// a transposition of two adjacent characters is counted as a single edit
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	// d[i][j] is the distance between s[:i] and t[:j]
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = min3(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] && d[i-2][j-2]+1 < d[i][j] {
+				d[i][j] = d[i-2][j-2] + 1
+			}
+		}
+	}
+
+	return d[len(s)][len(t)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,29 @@
+package suggest
+
+import (
+	"testing"
+)
+
+func TestClosest(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []string
+		want       string
+	}{
+		{name: "databse", candidates: []string{"level", "database"}, want: "database"},
+		{name: "APP_DATBASE_HOST", candidates: []string{"APP_DATABASE_HOST", "APP_DATABASE_PORT"}, want: "APP_DATABASE_HOST"},
+		{name: "Level", candidates: []string{"level"}, want: "level"},
+		{name: "a", candidates: []string{"bc"}, want: ""},
+		{name: "timeout", candidates: []string{"host", "port"}, want: ""},
+		{name: "APP_DB_PROT", candidates: []string{"APP_DB_HOST", "APP_DB_PORT"}, want: "APP_DB_PORT"},
+		{name: "APP_DB_TIMEOUT", candidates: []string{"APP_DB_HOST", "APP_DB_PORT"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Closest(tt.name, tt.candidates); got != tt.want {
+				t.Errorf("wrong suggestion %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
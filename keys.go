@@ -0,0 +1,577 @@
+package cleanenv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ilyakaznacheev/cleanenv/internal/suggest"
+	"gopkg.in/yaml.v3"
+	"olympos.io/encoding/edn"
+)
+
+// WithUnknownKeys sets how keys of the configuration file that don't match any structure field are reported.
+// By default, unknown keys are ignored.
+//
+// The file is checked before it is decoded. Every unknown key is reported with its line in the file
+// and a suggestion of the intended key if there is a similar one. Environment (.env) files are not checked.
+//
+// Example:
+//
+//	err := cleanenv.ReadConfig("config.yml", &cfg, cleanenv.WithUnknownKeys(cleanenv.CheckError))
+//	// config.yml:3: unknown key "databse", did you mean "database"?
+func WithUnknownKeys(mode CheckMode) Option {
+	return func(o *options) {
+		o.unknownKeys = mode
+	}
+}
+
+// fileValue is a value of the configuration file with the lines of its keys
+type fileValue struct {
+	// fields is the list of the object fields
+	fields []fileField
+	// items is the list of the array items
+	items []fileValue
+}
+
+// fileField is a key-value pair of the configuration file object
+type fileField struct {
+	key   string
+	line  int
+	value fileValue
+}
+
+// configFile is the configuration file read once and shared by the decoding and the key checks,
+// so all of them see the same content even if the file is rewritten meanwhile
+type configFile struct {
+	path   string
+	format string
+	data   []byte
+	// root is the key tree of the file, it is nil for .env files and files that can't be parsed
+	root *fileValue
+}
+
+// readConfigFile reads the configuration file and its key tree
+func readConfigFile(path string) (*configFile, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &configFile{path: path, format: fileFormat(path), data: data}
+	if file.format != "" && file.format != "env" {
+		// syntax errors are reported by the parser
+		if root, err := readFileTree(data, file.format); err == nil {
+			file.root = &root
+		}
+	}
+	return file, nil
+}
+
+// unknownKey is a key of the configuration file that doesn't match any structure field
+type unknownKey struct {
+	// path is the full key path, e.g. "database.prot"
+	path string
+	// key is the key itself, e.g. "prot"
+	key string
+	// line is the line of the key in the file, it is 0 if unknown
+	line int
+	// known is the list of known key paths on the same level
+	known []string
+}
+
+// checkFileKeys reports keys of the configuration file that don't match any structure field.
+//
+// YAML, JSON and TOML files are checked by the strict modes of their decoders, so the result matches the decoding.
+// The keys are matched with the structure fields only to find their lines and suggestions.
+// EDN decoder has no strict mode, so EDN files are checked by the key matching alone.
+func (o *options) checkFileKeys(file *configFile, cfg interface{}) error {
+	if o.unknownKeys == CheckIgnore || file.root == nil {
+		return nil
+	}
+
+	keys := make([]unknownKey, 0)
+	checkKeys(*file.root, reflect.TypeOf(cfg), file.format, "", func(k unknownKey) {
+		keys = append(keys, k)
+	})
+
+	switch file.format {
+	case "yaml":
+		keys = strictYAMLKeys(file.data, cfg, keys)
+	case "json":
+		keys = strictJSONKeys(file.data, cfg, keys)
+	case "toml":
+		keys = strictTOMLKeys(file.data, cfg, keys)
+	}
+
+	problems := make([]string, 0, len(keys))
+	for _, k := range keys {
+		problem := fmt.Sprintf("%s:%d: unknown key %q", file.path, k.line, k.path)
+		if k.line == 0 {
+			problem = fmt.Sprintf("%s: unknown key %q", file.path, k.path)
+		}
+		if s := suggest.Closest(k.path, k.known); s != "" {
+			problem += fmt.Sprintf(", did you mean %q?", s)
+		}
+		problems = append(problems, problem)
+	}
+
+	return o.check(o.unknownKeys, problems)
+}
+
+// yamlUnknownField matches the error of the YAML decoder about an unknown key
+var yamlUnknownField = regexp.MustCompile(`^line (\d+): field (.+) not found in type `)
+
+// strictYAMLKeys returns the unknown keys reported by the YAML decoder in the strict mode.
+// The keys found by the key matching provide the key paths and the suggestions.
+func strictYAMLKeys(data []byte, cfg interface{}, keys []unknownKey) []unknownKey {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	err := decoder.Decode(newConfig(cfg))
+	if err == io.EOF {
+		return nil
+	}
+	typeErr, ok := err.(*yaml.TypeError)
+	if err != nil && !ok {
+		// the keys can't be checked if the decoding fails for another reason
+		return keys
+	}
+
+	strict := make([]unknownKey, 0)
+	if typeErr != nil {
+		for _, msg := range typeErr.Errors {
+			m := yamlUnknownField.FindStringSubmatch(msg)
+			if m == nil {
+				continue
+			}
+			line, _ := strconv.Atoi(m[1])
+			strict = append(strict, unknownKey{path: m[2], key: m[2], line: line})
+		}
+	}
+
+	for i, s := range strict {
+		for _, k := range keys {
+			if k.line == s.line && k.key == s.key {
+				strict[i] = k
+				break
+			}
+		}
+	}
+
+	return strict
+}
+
+// strictJSONKeys returns the unknown keys if the JSON decoder rejects the file in the strict mode.
+// The decoder stops at the first unknown key, so all the keys found by the key matching are returned.
+func strictJSONKeys(data []byte, cfg interface{}, keys []unknownKey) []unknownKey {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(newConfig(cfg))
+	if err == nil {
+		return nil
+	}
+
+	msg := err.Error()
+	if !strings.HasPrefix(msg, "json: unknown field ") {
+		// the keys can't be checked if the decoding fails for another reason
+		return keys
+	}
+
+	if len(keys) == 0 {
+		key, _ := strconv.Unquote(strings.TrimPrefix(msg, "json: unknown field "))
+		keys = append(keys, unknownKey{path: key, key: key})
+	}
+	return keys
+}
+
+// strictTOMLKeys returns the keys left undecoded by the TOML decoder.
+// The keys found by the key matching provide the lines and the suggestions.
+func strictTOMLKeys(data []byte, cfg interface{}, keys []unknownKey) []unknownKey {
+	md, err := toml.NewDecoder(bytes.NewReader(data)).Decode(newConfig(cfg))
+	if err != nil {
+		// the keys can't be checked if the decoding fails
+		return keys
+	}
+
+	undecoded := make(map[string]bool)
+	for _, k := range md.Undecoded() {
+		undecoded[k.String()] = true
+	}
+
+	lines := strings.Split(string(data), "\n")
+	strict := make([]unknownKey, 0)
+	for _, k := range md.Undecoded() {
+		// keys of an undecoded table are reported with the table
+		if len(k) > 1 && undecoded[k[:len(k)-1].String()] {
+			continue
+		}
+
+		found := unknownKey{path: k.String(), key: k[len(k)-1]}
+		for _, known := range keys {
+			if known.path == found.path {
+				found = known
+				break
+			}
+		}
+		if found.line == 0 {
+			for i, line := range lines {
+				if tomlKeyLine(line, found.key) {
+					found.line = i + 1
+					break
+				}
+			}
+		}
+		strict = append(strict, found)
+	}
+
+	return strict
+}
+
+// newConfig returns a pointer to a new value of the configuration type
+func newConfig(cfg interface{}) interface{} {
+	t := reflect.TypeOf(cfg)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return reflect.New(t).Interface()
+}
+
+// fields determines which fields have keys in the configuration file, even if the file sets an empty value.
+// No fields are found if the file has no key tree, e.g. .env files are handled as environment variables.
+func (f *configFile) fields(cfg interface{}, metaInfo []structMeta) []bool {
+	found := make([]bool, len(metaInfo))
+	if f.root == nil {
+		return found
+	}
+
+	t := reflect.TypeOf(cfg)
+	for i := range metaInfo {
+		meta := &metaInfo[i]
+		found[i] = hasFileKey(*f.root, t, strings.Split(meta.path+meta.fieldName, "."), f.format)
+	}
+	return found
+}
+
+// hasFileKey determines if the file value has the key of the field with the path of field names
+func hasFileKey(v fileValue, t reflect.Type, names []string, format string) bool {
+	if len(names) == 0 {
+		return true
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	field, ok := t.FieldByName(names[0])
+	if !ok {
+		return false
+	}
+
+	for _, f := range keyFields(t, format) {
+		if f.Name != names[0] {
+			continue
+		}
+		for _, ff := range v.fields {
+			if ff.key == f.key || (format != "yaml" && strings.EqualFold(ff.key, f.key)) {
+				return hasFileKey(ff.value, field.Type, names[1:], format)
+			}
+		}
+		return false
+	}
+
+	// fields of embedded structures are flattened into the parent
+	return hasFileKey(v, field.Type, names[1:], format)
+}
+
+// readFileTree reads the keys of the configuration file
+func readFileTree(data []byte, format string) (fileValue, error) {
+	switch format {
+	case "yaml", "json":
+		// JSON is a subset of YAML, so both are read with line numbers by the YAML parser
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return fileValue{}, err
+		}
+		return yamlTree(&node), nil
+
+	case "toml":
+		var v map[string]interface{}
+		if _, err := toml.Decode(string(data), &v); err != nil {
+			return fileValue{}, err
+		}
+		return genericTree(reflect.ValueOf(v), strings.Split(string(data), "\n"), 0, tomlKeyLine), nil
+
+	case "edn":
+		var v interface{}
+		if err := edn.Unmarshal(data, &v); err != nil {
+			return fileValue{}, err
+		}
+		return genericTree(reflect.ValueOf(v), strings.Split(string(data), "\n"), 0, ednKeyLine), nil
+	}
+
+	return fileValue{}, nil
+}
+
+// yamlTree converts the YAML node into the file value
+func yamlTree(node *yaml.Node) fileValue {
+	var v fileValue
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			return yamlTree(node.Content[0])
+		}
+	case yaml.AliasNode:
+		return yamlTree(node.Alias)
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Tag == "!!merge" {
+				merged := yamlTree(value)
+				v.fields = append(v.fields, merged.fields...)
+				for _, item := range merged.items {
+					v.fields = append(v.fields, item.fields...)
+				}
+				continue
+			}
+			v.fields = append(v.fields, fileField{key: key.Value, line: key.Line, value: yamlTree(value)})
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			v.items = append(v.items, yamlTree(item))
+		}
+	}
+
+	return v
+}
+
+// genericTree converts the decoded value into the file value.
+// The lines of the keys are searched in the file text starting from the line of the parent key.
+func genericTree(value reflect.Value, lines []string, from int, keyLine func(line, key string) bool) fileValue {
+	var v fileValue
+
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Map:
+		keys := make([]string, 0, value.Len())
+		values := make(map[string]reflect.Value, value.Len())
+		for _, mapKey := range value.MapKeys() {
+			k := mapKey
+			if k.Kind() == reflect.Interface {
+				k = k.Elem()
+			}
+			key := fmt.Sprint(k.Interface())
+			if k.Kind() == reflect.String {
+				// EDN keywords are strings printed with a colon
+				key = k.String()
+			}
+			keys = append(keys, key)
+			values[key] = value.MapIndex(mapKey)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			line := 0
+			for i := from; i < len(lines); i++ {
+				if keyLine(lines[i], key) {
+					line = i + 1
+					break
+				}
+			}
+
+			next := from
+			if line > 0 {
+				next = line - 1
+			}
+			v.fields = append(v.fields, fileField{key: key, line: line, value: genericTree(values[key], lines, next, keyLine)})
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			v.items = append(v.items, genericTree(value.Index(i), lines, from, keyLine))
+		}
+	}
+
+	return v
+}
+
+// tomlKeyLine determines if the line of the TOML file defines the key or the table with the key
+func tomlKeyLine(line, key string) bool {
+	line = strings.TrimSpace(line)
+
+	if strings.HasPrefix(line, "[") {
+		for _, part := range strings.Split(strings.Trim(line, "[]"), ".") {
+			if strings.Trim(part, ` "'`) == key {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, k := range []string{key, `"` + key + `"`, "'" + key + "'"} {
+		if strings.HasPrefix(line, k) {
+			rest := strings.TrimSpace(line[len(k):])
+			if strings.HasPrefix(rest, "=") || strings.HasPrefix(rest, ".") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ednKeyLine determines if the line of the EDN file contains the key
+func ednKeyLine(line, key string) bool {
+	return strings.Contains(line, ":"+key) || strings.Contains(line, `"`+key+`"`)
+}
+
+// checkKeys calls unknown for each key of the file value that doesn't match any field of the type
+func checkKeys(v fileValue, t reflect.Type, format, path string, unknown func(unknownKey)) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if decodesItself(t, format) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if !isNestedStruct(t) {
+			return
+		}
+
+		fields := keyFields(t, format)
+		known := make([]string, 0, len(fields))
+		for _, f := range fields {
+			known = append(known, path+f.key)
+		}
+
+		for _, f := range v.fields {
+			field, ok := matchKey(fields, f.key, format)
+			if !ok {
+				unknown(unknownKey{path: path + f.key, key: f.key, line: f.line, known: known})
+				continue
+			}
+			checkKeys(f.value, field.Type, format, path+f.key+".", unknown)
+		}
+
+	case reflect.Map:
+		for _, f := range v.fields {
+			checkKeys(f.value, t.Elem(), format, path+f.key+".", unknown)
+		}
+
+	case reflect.Slice, reflect.Array:
+		for _, item := range v.items {
+			checkKeys(item, t.Elem(), format, path, unknown)
+		}
+	}
+}
+
+// keyField is a structure field with its key in the configuration file
+type keyField struct {
+	reflect.StructField
+	key string
+}
+
+// keyFields returns the fields of the structure decoded from the configuration file of the format.
+// Embedded structures are flattened the same way as the format decoder does.
+func keyFields(t reflect.Type, format string) []keyField {
+	fields := make([]keyField, 0, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		key, ok := fileKey(field, format)
+		if !ok {
+			continue
+		}
+
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		var inline bool
+		if format == "yaml" {
+			inline = strings.Contains(field.Tag.Get(format), ",inline")
+		} else {
+			inline = field.Anonymous && strings.Split(field.Tag.Get(format), ",")[0] == "" && ft.Kind() == reflect.Struct
+		}
+		if inline && ft.Kind() == reflect.Struct {
+			fields = append(fields, keyFields(ft, format)...)
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		fields = append(fields, keyField{field, key})
+	}
+
+	return fields
+}
+
+// matchKey returns the field with the key, the keys are case-insensitive in all formats except YAML
+func matchKey(fields []keyField, key, format string) (keyField, bool) {
+	for _, f := range fields {
+		if f.key == key {
+			return f, true
+		}
+	}
+
+	if format != "yaml" {
+		for _, f := range fields {
+			if strings.EqualFold(f.key, key) {
+				return f, true
+			}
+		}
+	}
+
+	return keyField{}, false
+}
+
+var (
+	yamlUnmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	tomlUnmarshalerType = reflect.TypeOf((*toml.Unmarshaler)(nil)).Elem()
+	ednUnmarshalerType  = reflect.TypeOf((*edn.Unmarshaler)(nil)).Elem()
+)
+
+// decodesItself determines if the type implements custom decoding of the format
+func decodesItself(t reflect.Type, format string) bool {
+	types := []reflect.Type{textUnmarshalerType}
+	switch format {
+	case "yaml":
+		types = append(types, yamlUnmarshalerType)
+	case "json":
+		types = append(types, jsonUnmarshalerType)
+	case "toml":
+		types = append(types, tomlUnmarshalerType)
+	case "edn":
+		types = append(types, ednUnmarshalerType)
+	}
+
+	for _, u := range types {
+		if t.Implements(u) || reflect.PtrTo(t).Implements(u) {
+			return true
+		}
+	}
+	return false
+}
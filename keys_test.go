@@ -0,0 +1,127 @@
+package cleanenv
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+type testKeysServer struct {
+	Host string `yaml:"host" json:"host" toml:"host" edn:"host"`
+	Port int    `yaml:"port" json:"port" toml:"port" edn:"port"`
+}
+
+type testKeysConfig struct {
+	Database testKeysServer            `yaml:"database" json:"database" toml:"database" edn:"database"`
+	Servers  []testKeysServer          `yaml:"servers" json:"servers" toml:"servers" edn:"servers"`
+	Labels   map[string]testKeysServer `yaml:"labels" json:"labels" toml:"labels" edn:"labels"`
+	Level    string                    `yaml:"level" json:"level" toml:"level" edn:"level"`
+	Skipped  string                    `yaml:"-" json:"-" toml:"-" edn:"-"`
+}
+
+type testKeysInline struct {
+	Level string                 `yaml:"level"`
+	Extra map[string]interface{} `yaml:",inline"`
+}
+
+func TestUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		ext  string
+		data string
+		cfg  interface{}
+		want string
+	}{
+		{
+			name: "yaml",
+			ext:  "*.yml",
+			data: "databse:\n  host: a\nservers:\n  - host: b\n    prot: 1\nlabels:\n  x:\n    hots: c\nlevel: info\nskipped: x\n",
+			want: `%[1]s:1: unknown key "databse", did you mean "database"?; ` +
+				`%[1]s:5: unknown key "servers.prot", did you mean "servers.port"?; ` +
+				`%[1]s:8: unknown key "labels.x.hots", did you mean "labels.x.host"?; ` +
+				`%[1]s:10: unknown key "skipped"`,
+		},
+		{
+			name: "yaml valid",
+			ext:  "*.yml",
+			data: "database:\n  host: a\nlevel: info\n",
+		},
+		{
+			name: "yaml inline map",
+			ext:  "*.yml",
+			data: "level: info\nplugins:\n  cache: true\n",
+			cfg:  &testKeysInline{},
+		},
+		{
+			name: "json",
+			ext:  "*.json",
+			data: "{\n  \"Database\": {\"host\": \"a\"},\n  \"servers\": [\n    {\"hots\": \"b\"}\n  ],\n  \"levl\": \"info\"\n}\n",
+			want: `%[1]s:4: unknown key "servers.hots", did you mean "servers.host"?; ` +
+				`%[1]s:6: unknown key "levl", did you mean "level"?`,
+		},
+		{
+			name: "toml",
+			ext:  "*.toml",
+			data: "level = \"info\"\n\n[database]\nhost = \"a\"\nprot = 1\n\n[[servers]]\nhots = \"b\"\n",
+			want: `%[1]s:5: unknown key "database.prot", did you mean "database.port"?; ` +
+				`%[1]s:8: unknown key "servers.hots", did you mean "servers.host"?`,
+		},
+		{
+			name: "edn",
+			ext:  "*.edn",
+			data: "{:level \"info\"\n :databse {:host \"a\"}}\n",
+			want: `%[1]s:2: unknown key "databse", did you mean "database"?`,
+		},
+		{
+			name: "env",
+			ext:  "*.env",
+			data: "UNKNOWN=1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile, err := ioutil.TempFile(os.TempDir(), tt.ext)
+			if err != nil {
+				t.Fatal("cannot create temporary file:", err)
+			}
+			defer os.Remove(tmpFile.Name())
+
+			if _, err = tmpFile.Write([]byte(tt.data)); err != nil {
+				t.Fatal("failed to write to temporary file:", err)
+			}
+
+			var cfg interface{} = &testKeysConfig{}
+			if tt.cfg != nil {
+				cfg = tt.cfg
+			}
+			if err = ReadConfig(tmpFile.Name(), cfg); err != nil {
+				t.Fatal("unexpected error with default options:", err)
+			}
+
+			err = ReadConfig(tmpFile.Name(), cfg, WithUnknownKeys(CheckError))
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("unexpected error %v", err)
+				}
+				return
+			}
+			if want := fmt.Sprintf(tt.want, tmpFile.Name()); err == nil || err.Error() != want {
+				t.Errorf("wrong error\n%v\nwant\n%s", err, want)
+			}
+
+			var buf bytes.Buffer
+			err = ReadConfig(tmpFile.Name(), cfg, WithUnknownKeys(CheckWarn), WithLogger(log.New(&buf, "", 0)))
+			if err != nil {
+				t.Fatal("unexpected error in warning mode:", err)
+			}
+			if got := strings.Count(buf.String(), "unknown key"); got != strings.Count(tt.want, "unknown key") {
+				t.Errorf("wrong warnings %s", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package cleanenv
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// envSkip is the env tag value that excludes the field from environment variables
+const envSkip = "-"
+
+// WithAutoEnv derives environment variable names of fields without the env tag from the field path.
+// Field and structure names are converted to SCREAMING_SNAKE_CASE and joined with the delimiter,
+// e.g. the field Database.MaxConns gets the name DATABASE__MAX_CONNS with the delimiter "__".
+//
+// A nested structure with the env-prefix tag uses the prefix instead of its name,
+// fields of embedded structures are named as the fields of the parent structure.
+// Use `env:"-"` to exclude a field from environment variables.
+//
+// Example:
+//
+//	type Config struct {
+//		Database struct {
+//			MaxConns int    // DATABASE__MAX_CONNS
+//			Password string `env:"-"`
+//		}
+//		Port int `env:"APP_PORT"`
+//	}
+//
+//	err := cleanenv.ReadEnv(&cfg, cleanenv.WithAutoEnv("__"))
+func WithAutoEnv(delimiter string) Option {
+	return func(o *options) {
+		o.autoEnv = true
+		o.autoEnvDelimiter = delimiter
+	}
+}
+
+// WithPrefix adds the prefix to the names of all environment variables, as env-prefix does for nested structures.
+// It allows to read the same structure several times with different prefixes.
+//
+// Example:
+//
+//	var primary, replica DatabaseConfig
+//
+//	// reads PRIMARY_HOST, PRIMARY_PORT, ...
+//	err := cleanenv.ReadEnv(&primary, cleanenv.WithPrefix("PRIMARY_"))
+//
+//	// reads REPLICA_HOST, REPLICA_PORT, ...
+//	err = cleanenv.ReadEnv(&replica, cleanenv.WithPrefix("REPLICA_"))
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// autoPrefix returns the prefix of derived environment variable names of the nested structure fields.
+// The prefix argument is the env-prefix of the nested structure including the prefixes of its parents.
+func (o *options) autoPrefix(parent cfgNode, field reflect.StructField, prefix string) string {
+	if _, ok := field.Tag.Lookup(TagEnvPrefix); ok {
+		return prefix
+	}
+	if field.Anonymous {
+		return parent.AutoPrefix
+	}
+	return parent.AutoPrefix + envName(field.Name) + o.autoEnvDelimiter
+}
+
+// envName converts the field name to SCREAMING_SNAKE_CASE, e.g. "MaxConns" to "MAX_CONNS" and "HTTPPort" to "HTTP_PORT"
+func envName(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+
+	return b.String()
+}
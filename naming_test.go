@@ -0,0 +1,244 @@
+package cleanenv
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestEnvName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Host", want: "HOST"},
+		{name: "MaxConns", want: "MAX_CONNS"},
+		{name: "HTTPPort", want: "HTTP_PORT"},
+		{name: "DBHost", want: "DB_HOST"},
+		{name: "ID", want: "ID"},
+		{name: "Level2", want: "LEVEL2"},
+		{name: "Retry2Times", want: "RETRY2_TIMES"},
+		{name: "snake_case", want: "SNAKE_CASE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := envName(tt.name); got != tt.want {
+				t.Errorf("wrong name %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutoEnv(t *testing.T) {
+	type Base struct {
+		Debug bool
+	}
+
+	type pool struct {
+		MaxConns int
+	}
+
+	type database struct {
+		Host     string
+		Password string `env:"-"`
+		Pool     pool
+	}
+
+	type config struct {
+		Base
+		Database database
+		Cache    pool `env-prefix:"CACHE_"`
+		Port     int  `env:"APP_PORT"`
+		Name     string
+	}
+
+	defer os.Clearenv()
+	os.Clearenv()
+	os.Setenv("DEBUG", "true")
+	os.Setenv("DATABASE__HOST", "localhost")
+	os.Setenv("DATABASE__PASSWORD", "secret")
+	os.Setenv("DATABASE__POOL__MAX_CONNS", "10")
+	os.Setenv("CACHE_MAX_CONNS", "5")
+	os.Setenv("APP_PORT", "8080")
+	os.Setenv("NAME", "app")
+
+	t.Run("read", func(t *testing.T) {
+		var cfg config
+		if err := ReadEnv(&cfg, WithAutoEnv("__")); err != nil {
+			t.Fatal(err)
+		}
+
+		want := config{
+			Base:     Base{Debug: true},
+			Database: database{Host: "localhost", Pool: pool{MaxConns: 10}},
+			Cache:    pool{MaxConns: 5},
+			Port:     8080,
+			Name:     "app",
+		}
+		if !reflect.DeepEqual(cfg, want) {
+			t.Errorf("wrong data %+v, want %+v", cfg, want)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		var cfg config
+		if err := ReadEnv(&cfg); err != nil {
+			t.Fatal(err)
+		}
+
+		want := config{Port: 8080}
+		if !reflect.DeepEqual(cfg, want) {
+			t.Errorf("wrong data %+v, want %+v", cfg, want)
+		}
+	})
+
+	t.Run("description", func(t *testing.T) {
+		want := "Environment variables:" +
+			"\n  APP_PORT int\n    \t" +
+			"\n  NAME string\n    \t" +
+			"\n\nBase:" +
+			"\n  DEBUG bool\n    \t" +
+			"\n\nDatabase:" +
+			"\n  DATABASE__HOST string\n    \t" +
+			"\n\nDatabase.Pool:" +
+			"\n  DATABASE__POOL__MAX_CONNS int\n    \t" +
+			"\n\nCache (CACHE_):" +
+			"\n  CACHE_MAX_CONNS int\n    \t"
+
+		got, err := GetDescription(&config{}, nil, WithAutoEnv("__"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("wrong description text %s, want %s", got, want)
+		}
+	})
+}
+
+func TestWithPrefix(t *testing.T) {
+	type pool struct {
+		MaxConns int `yaml:"max-conns" env:"MAX_CONNS" env-upd:""`
+	}
+
+	type database struct {
+		Host string `yaml:"host" env:"HOST" env-description:"database host"`
+		Port int    `yaml:"port" env:"PORT"`
+		Pool pool   `yaml:"pool" env-prefix:"POOL_"`
+	}
+
+	defer os.Clearenv()
+	os.Clearenv()
+	os.Setenv("PRIMARY_HOST", "primary")
+	os.Setenv("PRIMARY_POOL_MAX_CONNS", "10")
+	os.Setenv("REPLICA_HOST", "replica")
+	os.Setenv("REPLICA_PORT", "5433")
+	os.Setenv("HOST", "unprefixed")
+
+	t.Run("read env", func(t *testing.T) {
+		var primary, replica database
+		if err := ReadEnv(&primary, WithPrefix("PRIMARY_")); err != nil {
+			t.Fatal(err)
+		}
+		if err := ReadEnv(&replica, WithPrefix("REPLICA_")); err != nil {
+			t.Fatal(err)
+		}
+
+		if want := (database{Host: "primary", Pool: pool{MaxConns: 10}}); primary != want {
+			t.Errorf("wrong data %+v, want %+v", primary, want)
+		}
+		if want := (database{Host: "replica", Port: 5433}); replica != want {
+			t.Errorf("wrong data %+v, want %+v", replica, want)
+		}
+	})
+
+	t.Run("update env", func(t *testing.T) {
+		var cfg database
+		if err := ReadEnv(&cfg, WithPrefix("PRIMARY_")); err != nil {
+			t.Fatal(err)
+		}
+
+		os.Setenv("PRIMARY_POOL_MAX_CONNS", "20")
+		defer os.Setenv("PRIMARY_POOL_MAX_CONNS", "10")
+
+		if err := UpdateEnv(&cfg, WithPrefix("PRIMARY_")); err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Pool.MaxConns != 20 {
+			t.Errorf("wrong value %d, want 20", cfg.Pool.MaxConns)
+		}
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		var cfg database
+		if err := ReadEnv(&cfg, WithPrefix("PRIMARY_")); err != nil {
+			t.Fatal(err)
+		}
+
+		os.Setenv("PRIMARY_POOL_MAX_CONNS", "30")
+		defer os.Setenv("PRIMARY_POOL_MAX_CONNS", "10")
+
+		if _, err := reload("", &cfg, []Option{WithPrefix("PRIMARY_")}); err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Pool.MaxConns != 30 {
+			t.Errorf("wrong value %d, want 30", cfg.Pool.MaxConns)
+		}
+	})
+
+	t.Run("read config", func(t *testing.T) {
+		tmpFile, err := ioutil.TempFile(os.TempDir(), "*.yml")
+		if err != nil {
+			t.Fatal("cannot create temporary file:", err)
+		}
+		defer os.Remove(tmpFile.Name())
+
+		if _, err = tmpFile.Write([]byte("host: file\nport: 5432\n")); err != nil {
+			t.Fatal("failed to write to temporary file:", err)
+		}
+
+		var cfg database
+		if err := ReadConfig(tmpFile.Name(), &cfg, WithPrefix("REPLICA_")); err != nil {
+			t.Fatal(err)
+		}
+		if want := (database{Host: "replica", Port: 5433}); cfg != want {
+			t.Errorf("wrong data %+v, want %+v", cfg, want)
+		}
+	})
+
+	t.Run("auto env", func(t *testing.T) {
+		type config struct {
+			Database struct {
+				Host string
+			}
+		}
+
+		os.Setenv("APP__DATABASE__HOST", "auto")
+		defer os.Unsetenv("APP__DATABASE__HOST")
+
+		var cfg config
+		if err := ReadEnv(&cfg, WithPrefix("APP__"), WithAutoEnv("__")); err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Database.Host != "auto" {
+			t.Errorf("wrong value %q, want %q", cfg.Database.Host, "auto")
+		}
+	})
+
+	t.Run("description", func(t *testing.T) {
+		want := "Environment variables:" +
+			"\n  PRIMARY_HOST string\n    \tdatabase host" +
+			"\n  PRIMARY_PORT int\n    \t" +
+			"\n\nPool (PRIMARY_POOL_):" +
+			"\n  PRIMARY_POOL_MAX_CONNS int\n    \t"
+
+		got, err := GetDescription(&database{}, nil, WithPrefix("PRIMARY_"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("wrong description text %s, want %s", got, want)
+		}
+	})
+}
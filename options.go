@@ -0,0 +1,117 @@
+package cleanenv
+
+import (
+	"errors"
+	"log"
+	"os"
+	"strings"
+)
+
+// Option configures reading of the configuration
+type Option func(*options)
+
+// Logger reports warnings found while reading the configuration.
+// It is satisfied by *log.Logger.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// stdLogger writes warnings to the standard logger of the log package
+type stdLogger struct{}
+
+func (stdLogger) Printf(format string, v ...interface{}) {
+	log.Printf(format, v...)
+}
+
+// CheckMode defines how problems found by a configuration check are reported
+type CheckMode int
+
+// Configuration check modes
+const (
+	// CheckIgnore ignores the problems
+	CheckIgnore CheckMode = iota
+
+	// CheckWarn reports the problems to the logger
+	CheckWarn
+
+	// CheckError fails reading of the configuration
+	CheckError
+)
+
+// options is a set of configuration reading options
+type options struct {
+	// report is filled with the sources of the field values if set
+	report *Report
+	// sources is the list of sources of the field values by field path, the last one is effective
+	sources map[string][]Source
+	// usageTemplate is the template of the environment variables description
+	usageTemplate string
+	// logger reports warnings
+	logger Logger
+	// unknownKeys defines how unknown keys of the configuration file are reported
+	unknownKeys CheckMode
+	// unknownEnv defines how unknown environment variables with configured prefixes are reported
+	unknownEnv CheckMode
+	// duplicateEnv defines how environment variables used by more than one field are reported
+	duplicateEnv CheckMode
+	// prefix is the prefix of all environment variable names
+	prefix string
+	// normalizeEnv enables case-insensitive lookup of environment variables with "-" and "." treated as "_"
+	normalizeEnv bool
+	// autoEnv enables derivation of environment variable names from field names
+	autoEnv bool
+	// autoEnvDelimiter joins the derived names of nested structures and fields
+	autoEnvDelimiter string
+	// signals are the signals ReloadOnSignal reloads the configuration on
+	signals []os.Signal
+}
+
+// newOptions applies the options
+func newOptions(opts []Option) *options {
+	o := &options{
+		logger:       stdLogger{},
+		duplicateEnv: CheckError,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithLogger sets the logger for warnings. By default, warnings are written to the standard logger of the log package.
+func WithLogger(l Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithDuplicateEnv sets how environment variables used by more than one field are reported.
+// By default, such variables are reported as an error. Pass CheckIgnore to allow fields to share a variable intentionally.
+//
+// Example:
+//
+//	// both Primary.Timeout and Replica.Timeout are read from TIMEOUT
+//	err := cleanenv.ReadEnv(&cfg, cleanenv.WithDuplicateEnv(cleanenv.CheckIgnore))
+func WithDuplicateEnv(mode CheckMode) Option {
+	return func(o *options) {
+		o.duplicateEnv = mode
+	}
+}
+
+// check reports the problems according to the mode
+func (o *options) check(mode CheckMode, problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+
+	switch mode {
+	case CheckWarn:
+		for _, p := range problems {
+			o.logger.Printf("cleanenv: %s", p)
+		}
+	case CheckError:
+		return errors.New(strings.Join(problems, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package cleanenv
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"strings"
+	"text/template"
+)
+
+// markdownTemplate is the template of the Markdown configuration reference
+const markdownTemplate = `# {{.Header}}
+{{- if sections .Groups}}
+{{range .Groups}}{{if .Path}}
+- [{{.Path}}](#{{anchor .Path}}){{end}}{{end}}
+{{- end}}
+{{- range .Groups}}
+{{if .Path}}
+<a id="{{anchor .Path}}"></a>
+## {{.Path}}
+{{with .Prefix}}
+Prefix: ` + "`{{.}}`" + `
+{{end}}{{end}}
+| Variable | Type | Default | Required | Updatable | Aliases | Description |
+|----------|------|---------|----------|-----------|---------|-------------|
+{{- range .Fields}}
+| {{code (index .Env 0)}} | {{code .Type}} | {{with .Default}}{{code .}}{{end}} | {{yesno .Required}} | {{yesno .Updatable}} | {{range $i, $env := aliases .Env}}{{if $i}}, {{end}}{{code $env}}{{end}} | {{cell .Description}} |
+{{- end}}
+{{- end}}
+`
+
+// htmlTemplate is the template of the HTML configuration reference
+const htmlTemplate = `<h1>{{.Header}}</h1>
+{{- if sections .Groups}}
+<ul>
+{{- range .Groups}}{{if .Path}}
+  <li><a href="#{{anchor .Path}}">{{.Path}}</a></li>
+{{- end}}{{end}}
+</ul>
+{{- end}}
+{{- range .Groups}}
+{{- if .Path}}
+<h2 id="{{anchor .Path}}">{{.Path}}</h2>
+{{- with .Prefix}}
+<p>Prefix: <code>{{.}}</code></p>
+{{- end}}
+{{- end}}
+<table>
+  <thead>
+    <tr><th>Variable</th><th>Type</th><th>Default</th><th>Required</th><th>Updatable</th><th>Aliases</th><th>Description</th></tr>
+  </thead>
+  <tbody>
+{{- range .Fields}}
+    <tr><td><code>{{index .Env 0}}</code></td><td><code>{{.Type}}</code></td><td>{{with .Default}}<code>{{.}}</code>{{end}}</td><td>{{yesno .Required}}</td><td>{{yesno .Updatable}}</td><td>{{range $i, $env := aliases .Env}}{{if $i}}, {{end}}<code>{{$env}}</code>{{end}}</td><td>{{.Description}}</td></tr>
+{{- end}}
+  </tbody>
+</table>
+{{- end}}
+`
+
+// referenceFuncs are the functions available in the reference templates
+var referenceFuncs = map[string]interface{}{
+	"anchor":   referenceAnchor,
+	"sections": referenceSections,
+	"aliases":  referenceAliases,
+	"yesno":    referenceYesNo,
+	"code":     markdownCode,
+	"cell":     markdownCell,
+}
+
+// GenerateMarkdown returns the configuration reference in Markdown format.
+//
+// The reference contains a table of environment variables with their types, default values, descriptions and aliases
+// for each structure. Nested structures get their own sections with anchors, e.g. "#database" or "#app-cache".
+// Options such as WithAutoEnv change the listed variable names the same way as they do for reading.
+//
+// Example:
+//
+//	text, err := cleanenv.GenerateMarkdown(&cfg)
+//	if err != nil {
+//		...
+//	}
+//
+//	err = ioutil.WriteFile("CONFIGURATION.md", []byte(text), 0o644)
+func GenerateMarkdown(cfg interface{}, opts ...Option) (string, error) {
+	data, err := referenceData(cfg, opts)
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err := template.New("markdown").Funcs(referenceFuncs).Parse(markdownTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// GenerateHTML returns the configuration reference as an HTML fragment.
+//
+// The reference contains the same information as GenerateMarkdown does.
+// Nested structures get their own sections with anchors, e.g. "#database" or "#app-cache".
+func GenerateHTML(cfg interface{}, opts ...Option) (string, error) {
+	data, err := referenceData(cfg, opts)
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err := htmltemplate.New("html").Funcs(referenceFuncs).Parse(htmlTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// referenceData reads the fields with environment variables grouped by structure
+func referenceData(cfg interface{}, opts []Option) (UsageData, error) {
+	nodes, metaInfo, err := readStructTree(cfg, newOptions(opts))
+	if err != nil {
+		return UsageData{}, err
+	}
+
+	return usageData("Configuration", nodes, metaInfo), nil
+}
+
+// referenceAnchor returns the anchor of the structure section, e.g. "app-cache" for "App.Cache"
+func referenceAnchor(path string) string {
+	return strings.ToLower(strings.Replace(path, ".", "-", -1))
+}
+
+// referenceSections determines if there are nested structure sections
+func referenceSections(groups []UsageGroup) bool {
+	for _, g := range groups {
+		if g.Path != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// referenceAliases returns alternative environment variable names
+func referenceAliases(envList []string) []string {
+	return envList[1:]
+}
+
+// referenceYesNo formats the flag value
+func referenceYesNo(v bool) string {
+	if v {
+		return "yes"
+	}
+	return "no"
+}
+
+// markdownCode formats the text as inline code
+func markdownCode(text string) string {
+	if text == "" {
+		return `""`
+	}
+	return "`" + strings.Replace(text, "|", `\|`, -1) + "`"
+}
+
+// markdownCell escapes the text to be placed in a table cell
+func markdownCell(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+// markdownEscaper escapes characters breaking the table layout or interpreted as HTML
+var markdownEscaper = strings.NewReplacer(
+	"|", `\|`,
+	"\n", " ",
+	"<", "&lt;",
+	">", "&gt;",
+)
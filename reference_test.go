@@ -0,0 +1,99 @@
+package cleanenv
+
+import (
+	"testing"
+	"time"
+)
+
+type testReferenceConfig struct {
+	Timeout time.Duration `env:"TIMEOUT,TTL" env-description:"request timeout | max" env-default:"1s" env-upd:""`
+	Name    string
+	App     struct {
+		Port  int `env:"PORT" env-description:"app <port>" env-required:""`
+		Cache struct {
+			Type string `env:"TYPE" env-description:"cache type" env-default:""`
+		} `env-prefix:"CACHE_"`
+	} `env-prefix:"APP_"`
+}
+
+func TestGenerateMarkdown(t *testing.T) {
+	want := "# Configuration\n" +
+		"\n" +
+		"- [App](#app)\n" +
+		"- [App.Cache](#app-cache)\n" +
+		"\n" +
+		"| Variable | Type | Default | Required | Updatable | Aliases | Description |\n" +
+		"|----------|------|---------|----------|-----------|---------|-------------|\n" +
+		"| `TIMEOUT` | `time.Duration` | `1s` | no | yes | `TTL` | request timeout \\| max |\n" +
+		"\n" +
+		"<a id=\"app\"></a>\n" +
+		"## App\n" +
+		"\n" +
+		"Prefix: `APP_`\n" +
+		"\n" +
+		"| Variable | Type | Default | Required | Updatable | Aliases | Description |\n" +
+		"|----------|------|---------|----------|-----------|---------|-------------|\n" +
+		"| `APP_PORT` | `int` |  | yes | no |  | app &lt;port&gt; |\n" +
+		"\n" +
+		"<a id=\"app-cache\"></a>\n" +
+		"## App.Cache\n" +
+		"\n" +
+		"Prefix: `APP_CACHE_`\n" +
+		"\n" +
+		"| Variable | Type | Default | Required | Updatable | Aliases | Description |\n" +
+		"|----------|------|---------|----------|-----------|---------|-------------|\n" +
+		"| `APP_CACHE_TYPE` | `string` | \"\" | no | no |  | cache type |\n"
+
+	got, err := GenerateMarkdown(&testReferenceConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("wrong markdown\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err = GenerateMarkdown(42); err == nil {
+		t.Error("expected error but got nil")
+	}
+}
+
+func TestGenerateHTML(t *testing.T) {
+	head := "<table>\n" +
+		"  <thead>\n" +
+		"    <tr><th>Variable</th><th>Type</th><th>Default</th><th>Required</th><th>Updatable</th><th>Aliases</th><th>Description</th></tr>\n" +
+		"  </thead>\n" +
+		"  <tbody>\n"
+	tail := "  </tbody>\n" +
+		"</table>\n"
+
+	want := "<h1>Configuration</h1>\n" +
+		"<ul>\n" +
+		"  <li><a href=\"#app\">App</a></li>\n" +
+		"  <li><a href=\"#app-cache\">App.Cache</a></li>\n" +
+		"</ul>\n" +
+		head +
+		"    <tr><td><code>TIMEOUT</code></td><td><code>time.Duration</code></td><td><code>1s</code></td><td>no</td><td>yes</td><td><code>TTL</code></td><td>request timeout | max</td></tr>\n" +
+		tail +
+		"<h2 id=\"app\">App</h2>\n" +
+		"<p>Prefix: <code>APP_</code></p>\n" +
+		head +
+		"    <tr><td><code>APP_PORT</code></td><td><code>int</code></td><td></td><td>yes</td><td>no</td><td></td><td>app &lt;port&gt;</td></tr>\n" +
+		tail +
+		"<h2 id=\"app-cache\">App.Cache</h2>\n" +
+		"<p>Prefix: <code>APP_CACHE_</code></p>\n" +
+		head +
+		"    <tr><td><code>APP_CACHE_TYPE</code></td><td><code>string</code></td><td><code></code></td><td>no</td><td>no</td><td></td><td>cache type</td></tr>\n" +
+		tail
+
+	got, err := GenerateHTML(&testReferenceConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("wrong html\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err = GenerateHTML(42); err == nil {
+		t.Error("expected error but got nil")
+	}
+}
@@ -0,0 +1,261 @@
+package cleanenv
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+// SourceKind is a kind of a configuration value source
+type SourceKind string
+
+// Configuration value sources
+const (
+	// SourceDefault value from `env-default` tag
+	SourceDefault SourceKind = "default"
+
+	// SourceFile value from the configuration file
+	SourceFile SourceKind = "file"
+
+	// SourceEnv value from the environment variable
+	SourceEnv SourceKind = "env"
+)
+
+// Source describes where a configuration value came from
+type Source struct {
+	// Kind is the kind of the source
+	Kind SourceKind `json:"kind"`
+	// Name is the file path or the environment variable name
+	Name string `json:"name,omitempty"`
+	// Key is the field key in the configuration file
+	Key string `json:"key,omitempty"`
+	// Value is the field value set by the source, it is redacted for secret fields
+	Value interface{} `json:"value"`
+}
+
+// String returns a text representation of the source
+func (s Source) String() string {
+	switch s.Kind {
+	case SourceFile:
+		return fmt.Sprintf("file %s (%s)", s.Name, s.Key)
+	case SourceEnv:
+		return fmt.Sprintf("env %s", s.Name)
+	}
+	return string(s.Kind)
+}
+
+// FieldReport describes the final value of a configuration field and its sources
+type FieldReport struct {
+	// Path is the field path, e.g. "Database.Host"
+	Path string `json:"path"`
+	// Env is the list of environment variable names of the field
+	Env []string `json:"env,omitempty"`
+	// Value is the final field value, it is redacted for secret fields
+	Value interface{} `json:"value"`
+	// Source is the source of the final value, it is nil if the value wasn't set by any source
+	Source *Source `json:"source,omitempty"`
+	// Shadowed is the list of values set by lower-precedence sources and overwritten later
+	Shadowed []Source `json:"shadowed,omitempty"`
+}
+
+// Report describes where the values of the configuration came from.
+// It can be printed as a table or encoded into JSON.
+type Report struct {
+	Fields []FieldReport `json:"fields"`
+}
+
+// String returns the report as a table
+func (r *Report) String() string {
+	var buf bytes.Buffer
+
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "FIELD\tVALUE\tSOURCE\tSHADOWED")
+	for _, f := range r.Fields {
+		source := "-"
+		if f.Source != nil {
+			source = f.Source.String()
+		}
+
+		shadowed := make([]string, 0, len(f.Shadowed))
+		for _, s := range f.Shadowed {
+			shadowed = append(shadowed, fmt.Sprintf("%v from %s", s.Value, s))
+		}
+
+		fmt.Fprintf(w, "%s\t%v\t%s\t%s\n", f.Path, f.Value, source, strings.Join(shadowed, "; "))
+	}
+	w.Flush()
+
+	return buf.String()
+}
+
+// WithReport fills the report with the sources of the configuration values.
+//
+// Example:
+//
+//	var report cleanenv.Report
+//
+//	err := cleanenv.ReadConfig("config.yml", &cfg, cleanenv.WithReport(&report))
+//	if err != nil {
+//		...
+//	}
+//
+//	fmt.Println(report.String())
+func WithReport(r *Report) Option {
+	return func(o *options) {
+		o.report = r
+		o.sources = make(map[string][]Source)
+	}
+}
+
+// record stores the current field value as set by the source
+func (o *options) record(meta *structMeta, src Source) {
+	if o.report == nil {
+		return
+	}
+
+	// the value is copied, since later sources may modify slices and maps in place
+	src.Value = meta.reportValue()
+	if !meta.secret {
+		src.Value = copyValue(meta.fieldValue).Interface()
+	}
+	path := meta.path + meta.fieldName
+	o.sources[path] = append(o.sources[path], src)
+}
+
+// copyValue returns a deep copy of the value, unexported structure fields are copied shallowly
+func copyValue(v reflect.Value) reflect.Value {
+	c := reflect.New(v.Type()).Elem()
+
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return c
+		}
+		c.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(copyValue(v.Index(i)))
+		}
+
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(copyValue(v.Index(i)))
+		}
+
+	case reflect.Map:
+		if v.IsNil() {
+			return c
+		}
+		c.Set(reflect.MakeMapWithSize(v.Type(), v.Len()))
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(copyValue(iter.Key()), copyValue(iter.Value()))
+		}
+
+	case reflect.Ptr:
+		if v.IsNil() {
+			return c
+		}
+		c.Set(reflect.New(v.Type().Elem()))
+		c.Elem().Set(copyValue(v.Elem()))
+
+	case reflect.Interface:
+		if !v.IsNil() {
+			c.Set(copyValue(v.Elem()))
+		}
+
+	case reflect.Struct:
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(copyValue(v.Field(i)))
+			}
+		}
+
+	default:
+		c.Set(v)
+	}
+
+	return c
+}
+
+// recordFile records the fields with keys in the configuration file, even if the file sets the value the field already had
+func (o *options) recordFile(path string, cfg interface{}, metaInfo []structMeta, inFile []bool) {
+	if o.report == nil {
+		return
+	}
+
+	format := fileFormat(path)
+	if format == "env" {
+		// environment variables from .env file are recorded as env source
+		return
+	}
+
+	root := reflect.TypeOf(cfg)
+	if root.Kind() == reflect.Ptr {
+		root = root.Elem()
+	}
+
+	for i := range metaInfo {
+		meta := &metaInfo[i]
+		if !inFile[i] {
+			continue
+		}
+		o.record(meta, Source{Kind: SourceFile, Name: path, Key: meta.fileKeyPath(root, format)})
+	}
+}
+
+// fillReport fills the report with the final values and their sources
+func (o *options) fillReport(metaInfo []structMeta) {
+	if o.report == nil {
+		return
+	}
+
+	o.report.Fields = make([]FieldReport, 0, len(metaInfo))
+	for i := range metaInfo {
+		meta := &metaInfo[i]
+		path := meta.path + meta.fieldName
+
+		field := FieldReport{
+			Path:  path,
+			Env:   meta.envList,
+			Value: meta.reportValue(),
+		}
+
+		if sources := o.sources[path]; len(sources) > 0 {
+			field.Source = &sources[len(sources)-1]
+		}
+		if sources := o.sources[path]; len(sources) > 1 {
+			field.Shadowed = sources[:len(sources)-1]
+		}
+
+		o.report.Fields = append(o.report.Fields, field)
+	}
+}
+
+// reportValue returns the field value for the output, it is redacted for secret fields
+func (sm *structMeta) reportValue() interface{} {
+	if sm.secret {
+		return redactedValue
+	}
+	return sm.fieldValue.Interface()
+}
+
+// fileKeyPath returns the dot-separated path of the field keys in the configuration file of the given format
+func (sm *structMeta) fileKeyPath(root reflect.Type, format string) string {
+	keys := make([]string, 0)
+
+	t := root
+	for _, name := range strings.Split(sm.path+sm.fieldName, ".") {
+		field, ok := t.FieldByName(name)
+		if !ok {
+			break
+		}
+		key, _ := fileKey(field, format)
+		keys = append(keys, key)
+		t = field.Type
+	}
+
+	return strings.Join(keys, ".")
+}
@@ -0,0 +1,226 @@
+package cleanenv
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	type database struct {
+		Host     string `yaml:"host" env:"HOST" env-default:"localhost"`
+		Port     int    `yaml:"port" env:"PORT" env-default:"5432"`
+		Password string `yaml:"password" env:"PASSWORD" env-secret:""`
+	}
+
+	type config struct {
+		Database database `yaml:"db" env-prefix:"TEST_DB_"`
+		Level    string   `yaml:"level" env:"TEST_LEVEL" env-default:"info"`
+		Name     string
+	}
+
+	tmpFile, err := ioutil.TempFile(os.TempDir(), "*.yml")
+	if err != nil {
+		t.Fatal("cannot create temporary file:", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err = tmpFile.Write([]byte("db:\n  host: localhost\n  port: 5433\n  password: file\nlevel: debug\n")); err != nil {
+		t.Fatal("failed to write to temporary file:", err)
+	}
+
+	defer os.Clearenv()
+	os.Setenv("TEST_LEVEL", "warn")
+	os.Setenv("TEST_DB_PASSWORD", "env")
+
+	var (
+		cfg    config
+		report Report
+	)
+	if err = ReadConfig(tmpFile.Name(), &cfg, WithReport(&report)); err != nil {
+		t.Fatal(err)
+	}
+
+	file := tmpFile.Name()
+	want := []FieldReport{
+		{
+			Path:   "Level",
+			Env:    []string{"TEST_LEVEL"},
+			Value:  "warn",
+			Source: &Source{Kind: SourceEnv, Name: "TEST_LEVEL", Value: "warn"},
+			Shadowed: []Source{
+				{Kind: SourceDefault, Value: "info"},
+				{Kind: SourceFile, Name: file, Key: "level", Value: "debug"},
+			},
+		},
+		{
+			Path:  "Name",
+			Env:   []string{},
+			Value: "",
+		},
+		{
+			// the file sets the default value explicitly
+			Path:     "Database.Host",
+			Env:      []string{"TEST_DB_HOST"},
+			Value:    "localhost",
+			Source:   &Source{Kind: SourceFile, Name: file, Key: "db.host", Value: "localhost"},
+			Shadowed: []Source{{Kind: SourceDefault, Value: "localhost"}},
+		},
+		{
+			Path:     "Database.Port",
+			Env:      []string{"TEST_DB_PORT"},
+			Value:    5433,
+			Source:   &Source{Kind: SourceFile, Name: file, Key: "db.port", Value: 5433},
+			Shadowed: []Source{{Kind: SourceDefault, Value: 5432}},
+		},
+		{
+			Path:     "Database.Password",
+			Env:      []string{"TEST_DB_PASSWORD"},
+			Value:    "******",
+			Source:   &Source{Kind: SourceEnv, Name: "TEST_DB_PASSWORD", Value: "******"},
+			Shadowed: []Source{{Kind: SourceFile, Name: file, Key: "db.password", Value: "******"}},
+		},
+	}
+	if !reflect.DeepEqual(report.Fields, want) {
+		t.Errorf("wrong report %+v, want %+v", report.Fields, want)
+	}
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(report.Fields[3])
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"path":"Database.Port","env":["TEST_DB_PORT"],"value":5433,` +
+			`"source":{"kind":"file","name":"` + file + `","key":"db.port","value":5433},` +
+			`"shadowed":[{"kind":"default","value":5432}]}`
+		if string(data) != want {
+			t.Errorf("wrong json %s, want %s", data, want)
+		}
+	})
+}
+
+func TestReportFileSlice(t *testing.T) {
+	type config struct {
+		Tags   []string          `yaml:"tags" json:"tags" env:"TEST_TAGS" env-default:"a,b"`
+		Labels map[string]string `yaml:"labels" json:"labels" env:"TEST_LABELS"`
+	}
+
+	tests := []struct {
+		name string
+		ext  string
+		data string
+	}{
+		{name: "json", ext: "json", data: `{"tags": ["c", "d"], "labels": {"k": "v"}}`},
+		{name: "yaml", ext: "yml", data: "tags: [c, d]\nlabels: {k: v}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile, err := ioutil.TempFile(os.TempDir(), "*."+tt.ext)
+			if err != nil {
+				t.Fatal("cannot create temporary file:", err)
+			}
+			defer os.Remove(tmpFile.Name())
+
+			if _, err = tmpFile.Write([]byte(tt.data)); err != nil {
+				t.Fatal("failed to write to temporary file:", err)
+			}
+
+			var (
+				cfg    config
+				report Report
+			)
+			if err = ReadConfig(tmpFile.Name(), &cfg, WithReport(&report)); err != nil {
+				t.Fatal(err)
+			}
+
+			file := tmpFile.Name()
+			want := []FieldReport{
+				{
+					Path:     "Tags",
+					Env:      []string{"TEST_TAGS"},
+					Value:    []string{"c", "d"},
+					Source:   &Source{Kind: SourceFile, Name: file, Key: "tags", Value: []string{"c", "d"}},
+					Shadowed: []Source{{Kind: SourceDefault, Value: []string{"a", "b"}}},
+				},
+				{
+					Path:   "Labels",
+					Env:    []string{"TEST_LABELS"},
+					Value:  map[string]string{"k": "v"},
+					Source: &Source{Kind: SourceFile, Name: file, Key: "labels", Value: map[string]string{"k": "v"}},
+				},
+			}
+			if !reflect.DeepEqual(report.Fields, want) {
+				t.Errorf("wrong report %+v, want %+v", report.Fields, want)
+			}
+		})
+	}
+}
+
+func TestReportUpdateEnv(t *testing.T) {
+	type config struct {
+		Level string `env:"TEST_LEVEL" env-upd:""`
+		Host  string `env:"TEST_HOST"`
+	}
+
+	defer os.Clearenv()
+	os.Setenv("TEST_LEVEL", "info")
+	os.Setenv("TEST_HOST", "localhost")
+
+	var (
+		cfg    config
+		report Report
+	)
+	if err := UpdateEnv(&cfg, WithReport(&report)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []FieldReport{
+		{
+			Path:   "Level",
+			Env:    []string{"TEST_LEVEL"},
+			Value:  "info",
+			Source: &Source{Kind: SourceEnv, Name: "TEST_LEVEL", Value: "info"},
+		},
+		{
+			Path:  "Host",
+			Env:   []string{"TEST_HOST"},
+			Value: "",
+		},
+	}
+	if !reflect.DeepEqual(report.Fields, want) {
+		t.Errorf("wrong report %+v, want %+v", report.Fields, want)
+	}
+}
+
+func TestReportString(t *testing.T) {
+	type config struct {
+		Level    string `env:"TEST_LEVEL" env-default:"info"`
+		Host     string `env:"TEST_HOST" env-default:"localhost"`
+		Password string `env:"TEST_PASSWORD" env-secret:""`
+		Name     string
+	}
+
+	defer os.Clearenv()
+	os.Setenv("TEST_LEVEL", "debug")
+	os.Setenv("TEST_PASSWORD", "secret")
+
+	var (
+		cfg    config
+		report Report
+	)
+	if err := ReadEnv(&cfg, WithReport(&report)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "FIELD     VALUE      SOURCE             SHADOWED\n" +
+		"Level     debug      env TEST_LEVEL     info from default\n" +
+		"Host      localhost  default            \n" +
+		"Password  ******     env TEST_PASSWORD  \n" +
+		"Name                 -                  \n"
+	if got := report.String(); got != want {
+		t.Errorf("wrong table\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,295 @@
+package cleanenv
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// GenerateEnvExample returns the content of the .env example file.
+// It lists every environment variable with its default value, the description and the alternative names are added as comments.
+// Options such as WithAutoEnv change the variable names the same way as they do for reading.
+//
+// Example:
+//
+//	text, err := cleanenv.GenerateEnvExample(&cfg)
+//	if err != nil {
+//		...
+//	}
+//
+//	err = ioutil.WriteFile(".env.example", []byte(text), 0o644)
+func GenerateEnvExample(cfg interface{}, opts ...Option) (string, error) {
+	sample, err := sampleConfig(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	nodes, metaInfo, err := readStructTree(sample.Interface(), newOptions(opts))
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	for i, g := range usageData("", nodes, metaInfo).Groups {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		if g.Path != "" {
+			fmt.Fprintf(&buf, "# %s\n", g.Path)
+		}
+
+		for _, f := range g.Fields {
+			comment := f.Description
+			if f.Required {
+				comment += " (required)"
+			}
+			if len(f.Env) > 1 {
+				comment += fmt.Sprintf(" (alternatives: %s)", strings.Join(f.Env[1:], ", "))
+			}
+			writeComment(&buf, strings.TrimSpace(comment))
+
+			var value string
+			if f.Default != nil {
+				value = dotenvValue(*f.Default)
+			}
+			fmt.Fprintf(&buf, "%s=%s\n", f.Env[0], value)
+		}
+	}
+
+	return buf.String(), nil
+}
+
+// GenerateSample returns the content of the sample configuration file in the given format ("yaml", "json" or "toml").
+// Fields are filled with default values, and their descriptions are added as comments if the format allows.
+// Field keys are taken from the format tags (e.g. `yaml:"key"`) the same way as the format decoder does.
+//
+// Example:
+//
+//	text, err := cleanenv.GenerateSample(&cfg, "yaml")
+//	if err != nil {
+//		...
+//	}
+//
+//	err = ioutil.WriteFile("config.sample.yml", []byte(text), 0o644)
+func GenerateSample(cfg interface{}, format string) (string, error) {
+	sample, err := sampleConfig(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	switch format {
+	case "yaml":
+		return sampleYAML(sample)
+	case "json":
+		data, err := json.MarshalIndent(sample.Interface(), "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data) + "\n", nil
+	case "toml":
+		return sampleTOML(sample)
+	default:
+		return "", fmt.Errorf("file format '%s' is not supported by the generator", format)
+	}
+}
+
+// sampleConfig returns a pointer to a new configuration structure of the same type filled with default values
+func sampleConfig(cfg interface{}) (reflect.Value, error) {
+	t := reflect.TypeOf(cfg)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("wrong type %v, structure expected", reflect.ValueOf(cfg).Kind())
+	}
+
+	sample := reflect.New(t)
+
+	nodes, metaInfo, err := readStructTree(sample.Interface(), newOptions(nil))
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if err = setDefaults(nodes); err != nil {
+		return reflect.Value{}, err
+	}
+
+	if err = applyDefaults(metaInfo, false, newOptions(nil)); err != nil {
+		return reflect.Value{}, err
+	}
+
+	// map defaults are applied after all sources by the readers
+	for i := range metaInfo {
+		meta := &metaInfo[i]
+		if meta.defValue == nil || meta.fieldValue.Kind() != reflect.Map || !meta.isFieldValueZero() {
+			continue
+		}
+		if err = parseValue(meta.fieldValue, *meta.defValue, meta.separator, meta.layout); err != nil {
+			return reflect.Value{}, fmt.Errorf("parsing field %q: %v", meta.path+meta.fieldName, err)
+		}
+	}
+
+	return sample, nil
+}
+
+// sampleYAML encodes the sample configuration into YAML with field descriptions as comments
+func sampleYAML(sample reflect.Value) (string, error) {
+	var node yaml.Node
+	if err := node.Encode(sample.Interface()); err != nil {
+		return "", err
+	}
+	commentYAML(&node, sample.Elem().Type())
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// commentYAML sets descriptions of the structure fields as comments of the mapping keys
+func commentYAML(node *yaml.Node, t reflect.Type) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		field, ok := fieldByKey(t, key.Value, "yaml")
+		if !ok {
+			continue
+		}
+
+		key.HeadComment = field.Tag.Get(TagEnvDescription)
+		if isNestedStruct(field.Type) {
+			commentYAML(value, field.Type)
+		}
+	}
+}
+
+// sampleTOML encodes the sample configuration into TOML with field descriptions as comments
+func sampleTOML(sample reflect.Value) (string, error) {
+	var data bytes.Buffer
+	enc := toml.NewEncoder(&data)
+	enc.Indent = ""
+	if err := enc.Encode(sample.Interface()); err != nil {
+		return "", err
+	}
+
+	descriptions := make(map[string]string)
+	tomlDescriptions(sample.Elem().Type(), "", descriptions)
+
+	var (
+		buf   bytes.Buffer
+		table string
+	)
+
+	scanner := bufio.NewScanner(&data)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		var path string
+		switch {
+		case strings.HasPrefix(line, "["):
+			table = strings.Trim(line, "[]")
+			path = table
+		case strings.Contains(line, " = "):
+			key := line[:strings.Index(line, " = ")]
+			if k, err := strconv.Unquote(key); err == nil {
+				key = k
+			}
+			path = key
+			if table != "" {
+				path = table + "." + key
+			}
+		}
+
+		writeComment(&buf, descriptions[path])
+		buf.WriteString(line + "\n")
+	}
+
+	return buf.String(), scanner.Err()
+}
+
+// tomlDescriptions collects descriptions of the structure fields by their TOML key path
+func tomlDescriptions(t reflect.Type, prefix string, descriptions map[string]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		key, ok := fileKey(field, "toml")
+		if !ok {
+			continue
+		}
+
+		descriptions[prefix+key] = field.Tag.Get(TagEnvDescription)
+		if isNestedStruct(field.Type) {
+			tomlDescriptions(field.Type, prefix+key+".", descriptions)
+		}
+	}
+}
+
+// fieldByKey returns the exported structure field with the given key in the configuration file of the format
+func fieldByKey(t reflect.Type, key, format string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if k, ok := fileKey(field, format); ok && k == key {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// isNestedStruct determines if the type is a nested configuration structure
+func isNestedStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	_, found := validStructs[t]
+	return !found
+}
+
+// writeComment writes the text as a comment line by line
+func writeComment(buf *bytes.Buffer, text string) {
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		fmt.Fprintf(buf, "# %s\n", line)
+	}
+}
+
+// dotenvValue quotes the value for the .env file if needed
+func dotenvValue(value string) string {
+	if !strings.ContainsAny(value, " \t\n#\"'\\$=") {
+		return value
+	}
+	return `"` + dotenvEscaper.Replace(value) + `"`
+}
+
+// dotenvEscaper escapes special characters of the double-quoted .env value
+var dotenvEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"$", `\$`,
+)
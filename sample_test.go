@@ -0,0 +1,123 @@
+package cleanenv
+
+import (
+	"testing"
+	"time"
+)
+
+type testSampleConfig struct {
+	Timeout time.Duration     `yaml:"timeout" json:"timeout" toml:"timeout" env:"TIMEOUT,TTL" env-description:"request timeout" env-default:"1s"`
+	Name    string            `yaml:"name" json:"name" toml:"name" env:"NAME" env-default:"my app #1"`
+	Hosts   []string          `yaml:"hosts" json:"hosts" toml:"hosts" env:"HOSTS" env-default:"a,b"`
+	Labels  map[string]string `yaml:"labels" json:"labels" toml:"labels" env:"LABELS" env-default:"team:core"`
+	Server  struct {
+		Port int    `yaml:"port" json:"port" toml:"port" env:"PORT" env-description:"server port" env-required:""`
+		Host string `yaml:"host" json:"host" toml:"host" env:"HOST" env-description:"server host" env-default:"localhost"`
+	} `yaml:"server" json:"server" toml:"server" env-prefix:"SERVER_"`
+}
+
+func TestGenerateEnvExample(t *testing.T) {
+	want := "# request timeout (alternatives: TTL)\n" +
+		"TIMEOUT=1s\n" +
+		"NAME=\"my app #1\"\n" +
+		"HOSTS=a,b\n" +
+		"LABELS=team:core\n" +
+		"\n" +
+		"# Server\n" +
+		"# server port (required)\n" +
+		"SERVER_PORT=\n" +
+		"# server host\n" +
+		"SERVER_HOST=localhost\n"
+
+	got, err := GenerateEnvExample(&testSampleConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("wrong env example\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err = GenerateEnvExample(42); err == nil {
+		t.Error("expected error but got nil")
+	}
+}
+
+func TestGenerateSample(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "yaml",
+			format: "yaml",
+			want: "# request timeout\n" +
+				"timeout: 1s\n" +
+				"name: 'my app #1'\n" +
+				"hosts:\n" +
+				"  - a\n" +
+				"  - b\n" +
+				"labels:\n" +
+				"  team: core\n" +
+				"server:\n" +
+				"  # server port\n" +
+				"  port: 0\n" +
+				"  # server host\n" +
+				"  host: localhost\n",
+		},
+		{
+			name:   "json",
+			format: "json",
+			want: "{\n" +
+				"  \"timeout\": 1000000000,\n" +
+				"  \"name\": \"my app #1\",\n" +
+				"  \"hosts\": [\n" +
+				"    \"a\",\n" +
+				"    \"b\"\n" +
+				"  ],\n" +
+				"  \"labels\": {\n" +
+				"    \"team\": \"core\"\n" +
+				"  },\n" +
+				"  \"server\": {\n" +
+				"    \"port\": 0,\n" +
+				"    \"host\": \"localhost\"\n" +
+				"  }\n" +
+				"}\n",
+		},
+		{
+			name:   "toml",
+			format: "toml",
+			want: "# request timeout\n" +
+				"timeout = \"1s\"\n" +
+				"name = \"my app #1\"\n" +
+				"hosts = [\"a\", \"b\"]\n" +
+				"\n" +
+				"[labels]\n" +
+				"team = \"core\"\n" +
+				"\n" +
+				"[server]\n" +
+				"# server port\n" +
+				"port = 0\n" +
+				"# server host\n" +
+				"host = \"localhost\"\n",
+		},
+		{
+			name:    "unsupported format",
+			format:  "edn",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateSample(&testSampleConfig{}, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wrong error behavior %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("wrong sample\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package cleanenv
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// jsonSchemaDraft is the JSON Schema dialect of the generated schema
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// durationPattern matches duration strings accepted by time.ParseDuration
+const durationPattern = `^[-+]?(0|(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|ms|s|m|h))+)$`
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// jsonSchema is a JSON Schema of a configuration value
+type jsonSchema struct {
+	Schema      string        `json:"$schema,omitempty"`
+	Type        interface{}   `json:"type,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Default     interface{}   `json:"default,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+	Format      string        `json:"format,omitempty"`
+	Pattern     string        `json:"pattern,omitempty"`
+
+	Minimum       interface{} `json:"minimum,omitempty"`
+	Maximum       interface{} `json:"maximum,omitempty"`
+	MinLength     *int        `json:"minLength,omitempty"`
+	MaxLength     *int        `json:"maxLength,omitempty"`
+	MinItems      *int        `json:"minItems,omitempty"`
+	MaxItems      *int        `json:"maxItems,omitempty"`
+	MinProperties *int        `json:"minProperties,omitempty"`
+	MaxProperties *int        `json:"maxProperties,omitempty"`
+
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+}
+
+// GenerateJSONSchema returns the JSON Schema (draft 2020-12) of the configuration file.
+//
+// Property names are taken from `yaml` tags, or `json` tags if there are no `yaml` tags,
+// or derived from the field names the same way as the YAML decoder does.
+// Field descriptions, default values, required fields and validation rules are taken from the structure tags.
+//
+// Example:
+//
+//	schema, err := cleanenv.GenerateJSONSchema(&cfg)
+//	if err != nil {
+//		...
+//	}
+//
+//	err = ioutil.WriteFile("config.schema.json", []byte(schema), 0o644)
+func GenerateJSONSchema(cfg interface{}) (string, error) {
+	t := reflect.TypeOf(cfg)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return "", fmt.Errorf("wrong type %v, structure expected", reflect.ValueOf(cfg).Kind())
+	}
+
+	schema, err := structSchema(t, "")
+	if err != nil {
+		return "", err
+	}
+	schema.Schema = jsonSchemaDraft
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data) + "\n", nil
+}
+
+// structSchema returns the schema of the nested configuration structure
+func structSchema(t reflect.Type, path string) (*jsonSchema, error) {
+	schema := &jsonSchema{
+		Type:       "object",
+		Properties: make(map[string]*jsonSchema),
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		key, ok := schemaKey(field)
+		if !ok {
+			continue
+		}
+
+		fieldSchema, err := typeSchema(field.Type, path+field.Name+".")
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := field.Tag.Lookup(TagEnvLayout); ok && fieldSchema.Format == "date-time" {
+			// custom layouts are not RFC 3339 date-times
+			fieldSchema.Format = ""
+		}
+
+		if err = fieldSchema.applyTags(field); err != nil {
+			return nil, fmt.Errorf("field %q: %v", path+field.Name, err)
+		}
+
+		schema.Properties[key] = fieldSchema
+		if _, required := field.Tag.Lookup(TagEnvRequired); required {
+			schema.Required = append(schema.Required, key)
+		}
+	}
+
+	return schema, nil
+}
+
+// typeSchema returns the schema of the value type, path is used for nested structures errors
+func typeSchema(t reflect.Type, path string) (*jsonSchema, error) {
+	switch t {
+	case durationType:
+		// durations are strings in YAML and TOML files and nanoseconds in JSON files
+		return &jsonSchema{Type: []string{"string", "integer"}, Pattern: durationPattern}, nil
+	case reflect.TypeOf(time.Time{}):
+		return &jsonSchema{Type: "string", Format: "date-time"}, nil
+	case reflect.TypeOf(url.URL{}):
+		return &jsonSchema{Type: "string", Format: "uri"}, nil
+	case reflect.TypeOf(&time.Location{}):
+		return &jsonSchema{Type: "string"}, nil
+	}
+
+	if t.Implements(textUnmarshalerType) || reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return &jsonSchema{Type: "string"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &jsonSchema{Type: "string"}, nil
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &jsonSchema{Type: "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &jsonSchema{Type: "integer", Minimum: 0}, nil
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number"}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := typeSchema(t.Elem(), path)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonSchema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		values, err := typeSchema(t.Elem(), path)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonSchema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Ptr:
+		return typeSchema(t.Elem(), path)
+	case reflect.Struct:
+		return structSchema(t, path)
+	}
+
+	// any value
+	return &jsonSchema{}, nil
+}
+
+// applyTags sets the field description, the default value and the validation rules from the field tags
+func (s *jsonSchema) applyTags(field reflect.StructField) error {
+	s.Description = field.Tag.Get(TagEnvDescription)
+
+	separator := DefaultSeparator
+	if sep, ok := field.Tag.Lookup(TagEnvSeparator); ok {
+		separator = sep
+	}
+	layout := lookupTag(field.Tag, TagEnvLayout)
+
+	value := func(tag, raw string) (interface{}, error) {
+		v, err := schemaValue(field.Type, raw, separator, layout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q: %v", tag, raw, err)
+		}
+		return v, nil
+	}
+
+	if def, ok := field.Tag.Lookup(TagEnvDefault); ok {
+		v, err := value(TagEnvDefault, def)
+		if err != nil {
+			return err
+		}
+		s.Default = v
+	}
+
+	if values, ok := field.Tag.Lookup(TagEnvOneOf); ok {
+		for _, raw := range strings.Split(values, DefaultSeparator) {
+			v, err := value(TagEnvOneOf, strings.TrimSpace(raw))
+			if err != nil {
+				return err
+			}
+			s.Enum = append(s.Enum, v)
+		}
+	}
+
+	if pattern, ok := field.Tag.Lookup(TagEnvPattern); ok {
+		s.Pattern = pattern
+	}
+
+	bounds := []struct {
+		tag      string
+		min, max bool
+	}{
+		{TagEnvMin, true, false},
+		{TagEnvMax, false, true},
+		{TagEnvLen, true, true},
+	}
+	for _, b := range bounds {
+		raw, ok := field.Tag.Lookup(b.tag)
+		if !ok {
+			continue
+		}
+		if err := s.applyBound(field.Type, b.tag, raw, b.min, b.max); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyBound sets the minimal and (or) maximal value or length
+func (s *jsonSchema) applyBound(t reflect.Type, tag, raw string, min, max bool) error {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var minLen, maxLen **int
+	switch t.Kind() {
+	case reflect.String:
+		minLen, maxLen = &s.MinLength, &s.MaxLength
+	case reflect.Slice, reflect.Array:
+		minLen, maxLen = &s.MinItems, &s.MaxItems
+	case reflect.Map:
+		minLen, maxLen = &s.MinProperties, &s.MaxProperties
+	}
+
+	if minLen != nil {
+		var n int
+		if _, err := fmt.Sscan(raw, &n); err != nil {
+			return fmt.Errorf("invalid %s value %q: %v", tag, raw, err)
+		}
+		if min {
+			*minLen = &n
+		}
+		if max {
+			*maxLen = &n
+		}
+		return nil
+	}
+
+	if s.Type != "integer" && s.Type != "number" {
+		// bounds of durations and times can't be expressed in the schema
+		return nil
+	}
+
+	v, err := schemaValue(t, raw, DefaultSeparator, nil)
+	if err != nil {
+		return fmt.Errorf("invalid %s value %q: %v", tag, raw, err)
+	}
+	if min {
+		s.Minimum = v
+	}
+	if max {
+		s.Maximum = v
+	}
+	return nil
+}
+
+// schemaValue parses the tag value into the value of the field type.
+// Values encoded as strings in the configuration file are returned as is.
+func schemaValue(t reflect.Type, raw, sep string, layout *string) (interface{}, error) {
+	v := reflect.New(t).Elem()
+	if err := parseValue(v, raw, sep, layout); err != nil {
+		return nil, err
+	}
+
+	if t == durationType {
+		return raw, nil
+	}
+	if s, err := typeSchema(t, ""); err == nil && s.Type == "string" {
+		return raw, nil
+	}
+	return v.Interface(), nil
+}
+
+// schemaKey returns the property name of the field taken from `yaml` or `json` tags.
+// It returns false if the field is skipped.
+func schemaKey(field reflect.StructField) (string, bool) {
+	if _, ok := field.Tag.Lookup("yaml"); ok {
+		return fileKey(field, "yaml")
+	}
+	if _, ok := field.Tag.Lookup("json"); ok {
+		return fileKey(field, "json")
+	}
+	return fileKey(field, "yaml")
+}
@@ -0,0 +1,107 @@
+package cleanenv
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGenerateJSONSchema(t *testing.T) {
+	type item struct {
+		Name string `json:"name" env-required:""`
+	}
+
+	type config struct {
+		Timeout time.Duration  `yaml:"timeout" env-description:"request timeout" env-default:"1s"`
+		Level   string         `yaml:"level" env-oneof:"debug,info" env-default:"info"`
+		Port    uint16         `json:"port" env-min:"1" env-max:"65535" env-required:""`
+		Code    string         `yaml:"code" env-len:"2" env-pattern:"^[A-Z]+$"`
+		Hosts   []string       `yaml:"hosts" env-default:"a,b" env-min:"1"`
+		Labels  map[string]int `yaml:"labels"`
+		Started time.Time      `yaml:"started"`
+		Date    time.Time      `yaml:"date" env-layout:"2006-01-02"`
+		Items   []item         `yaml:"items"`
+		Skipped string         `yaml:"-"`
+		Server  struct {
+			Host string `env-default:"localhost"`
+		} `yaml:"server" env-description:"server settings"`
+	}
+
+	want := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"timeout": {
+				"type": ["string", "integer"],
+				"description": "request timeout",
+				"default": "1s",
+				"pattern": "^[-+]?(0|(([0-9]+(\\.[0-9]*)?|\\.[0-9]+)(ns|us|µs|ms|s|m|h))+)$"
+			},
+			"level": {"type": "string", "default": "info", "enum": ["debug", "info"]},
+			"port": {"type": "integer", "minimum": 1, "maximum": 65535},
+			"code": {"type": "string", "pattern": "^[A-Z]+$", "minLength": 2, "maxLength": 2},
+			"hosts": {"type": "array", "default": ["a", "b"], "minItems": 1, "items": {"type": "string"}},
+			"labels": {"type": "object", "additionalProperties": {"type": "integer"}},
+			"started": {"type": "string", "format": "date-time"},
+			"date": {"type": "string"},
+			"items": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {"name": {"type": "string"}},
+					"required": ["name"]
+				}
+			},
+			"server": {
+				"type": "object",
+				"description": "server settings",
+				"properties": {"host": {"type": "string", "default": "localhost"}}
+			}
+		},
+		"required": ["port"]
+	}`
+
+	got, err := GenerateJSONSchema(&config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotSchema, wantSchema interface{}
+	if err = json.Unmarshal([]byte(got), &gotSchema); err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal([]byte(want), &wantSchema); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotSchema, wantSchema) {
+		t.Errorf("wrong schema\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateJSONSchemaErrors(t *testing.T) {
+	type invalidDefault struct {
+		Port int `env-default:"port"`
+	}
+
+	type invalidMin struct {
+		Name string `env-min:"one"`
+	}
+
+	tests := []struct {
+		name string
+		cfg  interface{}
+	}{
+		{name: "not a structure", cfg: 42},
+		{name: "invalid default", cfg: &invalidDefault{}},
+		{name: "invalid min", cfg: invalidMin{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := GenerateJSONSchema(tt.cfg); err == nil {
+				t.Error("expected error but got nil")
+			}
+		})
+	}
+}
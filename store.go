@@ -0,0 +1,126 @@
+//go:build go1.21
+// +build go1.21
+
+package cleanenv
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Store is a concurrency-safe configuration holder.
+//
+// It keeps an immutable snapshot of the configuration. Load returns the current snapshot,
+// and Reload builds a new one and replaces the current snapshot atomically, so readers never observe a partially updated configuration.
+// Snapshots returned by Load must not be modified.
+//
+// Example:
+//
+//	store, err := cleanenv.NewStore[Config]("config.yml")
+//	if err != nil {
+//		...
+//	}
+//
+//	cfg := store.Load()
+type Store[T any] struct {
+	path    string
+	opts    []Option
+	current atomic.Pointer[T]
+
+	// reloadMu serializes reloads, so a snapshot read earlier never replaces a newer one
+	reloadMu sync.Mutex
+
+	mu          sync.Mutex
+	subscribers []subscriber[T]
+	nextID      int
+}
+
+// subscriber is a registered change notification function
+type subscriber[T any] struct {
+	id int
+	fn func(old, new *T)
+}
+
+// NewStore creates a store and reads the initial configuration.
+// The configuration is read from the file and environment variables the same way as ReadConfig does,
+// or as ReadEnv does if the path is empty. The options are applied to the initial read and every reload.
+func NewStore[T any](path string, opts ...Option) (*Store[T], error) {
+	s := &Store[T]{path: path, opts: opts}
+
+	cfg, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	s.current.Store(cfg)
+
+	return s, nil
+}
+
+// Load returns the current configuration snapshot
+func (s *Store[T]) Load() *T {
+	return s.current.Load()
+}
+
+// Reload reads a new configuration snapshot and replaces the current one.
+// If the configuration can't be read, the current snapshot is kept.
+// Subscribers are notified after the snapshot is replaced.
+// Concurrent reloads are performed one after another.
+func (s *Store[T]) Reload() error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	cfg, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old := s.current.Swap(cfg)
+	for _, sub := range s.subscribers {
+		sub.fn(old, cfg)
+	}
+
+	return nil
+}
+
+// Subscribe registers a function called after each successful reload with the previous and the new snapshot.
+// It returns a function to cancel the subscription.
+// Subscribers are called synchronously by Reload and must not call Reload or Subscribe.
+func (s *Store[T]) Subscribe(fn func(old, new *T)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextID
+	s.nextID++
+	s.subscribers = append(s.subscribers, subscriber[T]{id, fn})
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, sub := range s.subscribers {
+			if sub.id == id {
+				s.subscribers = append(s.subscribers[:i:i], s.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// read reads a new configuration value
+func (s *Store[T]) read() (*T, error) {
+	cfg := new(T)
+
+	var err error
+	if s.path != "" {
+		err = ReadConfig(s.path, cfg, s.opts...)
+	} else {
+		err = ReadEnv(cfg, s.opts...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,182 @@
+//go:build go1.21
+// +build go1.21
+
+package cleanenv
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testStoreConfig struct {
+	Host  string `env:"TEST_STORE_HOST" env-default:"localhost"`
+	Level string `env:"TEST_STORE_LEVEL" env-oneof:"debug,info"`
+}
+
+func TestStore(t *testing.T) {
+	defer os.Clearenv()
+
+	os.Setenv("TEST_STORE_LEVEL", "info")
+
+	store, err := NewStore[testStoreConfig]("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := store.Load()
+	if want := (testStoreConfig{Host: "localhost", Level: "info"}); *first != want {
+		t.Fatalf("wrong data %v, want %v", *first, want)
+	}
+
+	var changes [][2]testStoreConfig
+	cancel := store.Subscribe(func(old, new *testStoreConfig) {
+		changes = append(changes, [2]testStoreConfig{*old, *new})
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		os.Setenv("TEST_STORE_LEVEL", "debug")
+		if err := store.Reload(); err != nil {
+			t.Fatal(err)
+		}
+
+		if want := (testStoreConfig{Host: "localhost", Level: "debug"}); *store.Load() != want {
+			t.Errorf("wrong data %v, want %v", *store.Load(), want)
+		}
+		if first.Level != "info" {
+			t.Errorf("previous snapshot was modified: %v", *first)
+		}
+		if len(changes) != 1 || changes[0][0].Level != "info" || changes[0][1].Level != "debug" {
+			t.Errorf("wrong change notifications %v", changes)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		os.Setenv("TEST_STORE_LEVEL", "trace")
+		if err := store.Reload(); err == nil {
+			t.Fatal("expected error but got nil")
+		}
+
+		if store.Load().Level != "debug" {
+			t.Errorf("wrong data %v, want previous snapshot", *store.Load())
+		}
+		if len(changes) != 1 {
+			t.Errorf("unexpected change notification %v", changes)
+		}
+	})
+
+	t.Run("unsubscribe", func(t *testing.T) {
+		cancel()
+		os.Setenv("TEST_STORE_LEVEL", "info")
+		if err := store.Reload(); err != nil {
+			t.Fatal(err)
+		}
+		if len(changes) != 1 {
+			t.Errorf("unexpected change notification %v", changes)
+		}
+	})
+
+	t.Run("concurrent access", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				_ = store.Load().Level
+			}()
+			go func() {
+				defer wg.Done()
+				_ = store.Reload()
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestNewStoreError(t *testing.T) {
+	if _, err := NewStore[testStoreConfig]("invalid file path"); err == nil {
+		t.Error("expected error for invalid file path")
+	}
+}
+
+// testStoreReads receives a channel each time testStoreSlowConfig is read, the read is blocked until the channel is closed
+var testStoreReads chan chan struct{}
+
+type testStoreSlowConfig struct {
+	Host string `env:"TEST_STORE_HOST"`
+}
+
+func (c *testStoreSlowConfig) SetDefaults() error {
+	if testStoreReads != nil {
+		release := make(chan struct{})
+		testStoreReads <- release
+		<-release
+	}
+	return nil
+}
+
+func TestStoreReloadSerialized(t *testing.T) {
+	defer os.Clearenv()
+
+	store, err := NewStore[testStoreSlowConfig]("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testStoreReads = make(chan chan struct{})
+	defer func() { testStoreReads = nil }()
+
+	errs := make(chan error, 2)
+	reload := func() {
+		errs <- store.Reload()
+	}
+
+	// the first reload reads the old value and waits
+	os.Setenv("TEST_STORE_HOST", "old")
+	go reload()
+	first := <-testStoreReads
+
+	// the second reload must not start reading until the first one is finished
+	os.Setenv("TEST_STORE_HOST", "new")
+	go reload()
+
+	select {
+	case release := <-testStoreReads:
+		close(release)
+		t.Fatal("reloads overlap")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(first)
+	close(<-testStoreReads)
+
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := store.Load().Host; got != "new" {
+		t.Errorf("wrong data %q, want %q", got, "new")
+	}
+}
+
+func TestStoreOptions(t *testing.T) {
+	defer os.Clearenv()
+
+	os.Setenv("APP_TEST_STORE_LEVEL", "info")
+
+	store, err := NewStore[testStoreConfig]("", WithPrefix("APP_"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("APP_TEST_STORE_LEVEL", "debug")
+	if err := store.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if want := (testStoreConfig{Host: "localhost", Level: "debug"}); *store.Load() != want {
+		t.Errorf("wrong data %v, want %v", *store.Load(), want)
+	}
+}
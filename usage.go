@@ -0,0 +1,141 @@
+package cleanenv
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// DefaultUsageTemplate is the template of the environment variables description returned by GetDescription.
+//
+// The template is executed with UsageData. Besides the standard template functions,
+// functions "join" (strings.Join) and "quote" (strconv.Quote) are available.
+const DefaultUsageTemplate = `{{.Header}}
+{{- range .Groups}}
+{{- if .Path}}
+
+{{.Path}}{{with .Prefix}} ({{.}}){{end}}:
+{{- end}}
+{{- range .Fields}}{{$f := .}}
+{{- range $i, $env := .Env}}
+  {{$env}} {{$f.Type}}{{if $i}} (alternative to {{index $f.Env 0}}){{end}}
+    	{{$f.Description}}
+{{- if $f.Required}} (required){{end}}
+{{- with $f.Default}} (default {{quote .}}){{end}}
+{{- with $f.Min}} (min {{.}}){{end}}
+{{- with $f.Max}} (max {{.}}){{end}}
+{{- with $f.Len}} (length {{.}}){{end}}
+{{- with $f.OneOf}} (one of {{quote (join . ",")}}){{end}}
+{{- with $f.Pattern}} (pattern {{quote .}}){{end}}
+{{- with $f.RequiredIf}} (required if {{.}}){{end}}
+{{- with $f.RequiredWith}} (required with {{join . ","}}){{end}}
+{{- with $f.ExclusiveGroup}} (exclusive group {{quote .}}){{end}}
+{{- end}}
+{{- range .Deprecated}}
+  {{.}} {{$f.Type}} (deprecated, use {{index $f.Env 0}})
+    	{{$f.Description}}
+{{- end}}
+{{- end}}
+{{- end}}`
+
+// usageFuncs are the functions available in the usage template
+var usageFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"quote": strconv.Quote,
+}
+
+// UsageData is the data the usage template is executed with
+type UsageData struct {
+	// Header is the header text
+	Header string
+	// Groups is the list of field groups, one per structure.
+	// The root structure goes first, nested structures follow their parents in the order of declaration.
+	Groups []UsageGroup
+}
+
+// UsageGroup is a group of fields of a structure
+type UsageGroup struct {
+	// Path is the structure path, e.g. "Database", it is empty for the root structure
+	Path string
+	// Prefix is the environment variable prefix of the structure
+	Prefix string
+	// Fields is the list of fields with environment variables
+	Fields []FieldInfo
+}
+
+// WithUsageTemplate replaces the template of the environment variables description.
+// The template is executed with UsageData, see DefaultUsageTemplate for an example.
+//
+// Example:
+//
+//	text, err := cleanenv.GetDescription(&cfg, nil, cleanenv.WithUsageTemplate(
+//		`{{range .Groups}}{{range .Fields}}{{join .Env ", "}}: {{.Description}}{{"\n"}}{{end}}{{end}}`,
+//	))
+func WithUsageTemplate(text string) Option {
+	return func(o *options) {
+		o.usageTemplate = text
+	}
+}
+
+// usageData groups the fields with environment variables by structure
+func usageData(header string, nodes []cfgNode, metaInfo []structMeta) UsageData {
+	byPath := make(map[string]*UsageGroup, len(nodes))
+	for _, n := range nodes {
+		byPath[n.Path] = &UsageGroup{
+			Path:   strings.TrimSuffix(n.Path, "."),
+			Prefix: n.Prefix,
+		}
+	}
+
+	for i := range metaInfo {
+		if len(metaInfo[i].envList) == 0 {
+			continue
+		}
+		g := byPath[metaInfo[i].path]
+		g.Fields = append(g.Fields, metaInfo[i].fieldInfo())
+	}
+
+	data := UsageData{Header: header}
+
+	// list nested structures right after their parents
+	var walk func(path string)
+	walk = func(path string) {
+		if g := byPath[path]; len(g.Fields) > 0 {
+			data.Groups = append(data.Groups, *g)
+		}
+		for _, n := range nodes {
+			if n.Path != path && parentPath(n.Path) == path {
+				walk(n.Path)
+			}
+		}
+	}
+	walk("")
+
+	return data
+}
+
+// parentPath returns the path of the parent structure, e.g. "App." for "App.Cache."
+func parentPath(path string) string {
+	path = strings.TrimSuffix(path, ".")
+	return path[:strings.LastIndex(path, ".")+1]
+}
+
+// executeUsage renders the usage template
+func executeUsage(text string, data UsageData) (string, error) {
+	if text == "" {
+		text = DefaultUsageTemplate
+	}
+
+	tmpl, err := template.New("usage").Funcs(usageFuncs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
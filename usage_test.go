@@ -0,0 +1,82 @@
+package cleanenv
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetDescriptionTypes(t *testing.T) {
+	type server struct {
+		Host string `env:"HOST" env-description:"server host" env-required:""`
+	}
+
+	type config struct {
+		Timeout time.Duration     `env:"TIMEOUT" env-description:"timeout" env-default:"5s"`
+		Started time.Time         `env:"STARTED" env-description:"start time"`
+		Hosts   []string          `env:"HOSTS" env-description:"hosts"`
+		Labels  map[string]string `env:"LABELS" env-description:"labels"`
+		Server  server            `env-prefix:"SERVER_"`
+	}
+
+	want := "Environment variables:" +
+		"\n  TIMEOUT time.Duration\n    \ttimeout (default \"5s\")" +
+		"\n  STARTED time.Time\n    \tstart time" +
+		"\n  HOSTS []string\n    \thosts" +
+		"\n  LABELS map[string]string\n    \tlabels" +
+		"\n\nServer (SERVER_):" +
+		"\n  SERVER_HOST string\n    \tserver host (required)"
+
+	got, err := GetDescription(&config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("wrong description text %s, want %s", got, want)
+	}
+}
+
+func TestGetDescriptionTemplate(t *testing.T) {
+	type database struct {
+		Host string `env:"HOST,ADDR" env-description:"database host"`
+	}
+
+	type config struct {
+		Level    string   `env:"LEVEL" env-description:"log level"`
+		Database database `env-prefix:"DB_"`
+	}
+
+	tests := []struct {
+		name    string
+		tmpl    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "custom template",
+			tmpl: `{{.Header}}{{range .Groups}}{{range .Fields}} {{.Path}}={{join .Env "|"}}{{end}}{{end}}`,
+			want: "Environment variables: Level=LEVEL Database.Host=DB_HOST|DB_ADDR",
+		},
+		{
+			name:    "invalid template",
+			tmpl:    `{{.Header`,
+			wantErr: true,
+		},
+		{
+			name:    "execution error",
+			tmpl:    `{{.Unknown}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetDescription(&config{}, nil, WithUsageTemplate(tt.tmpl))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wrong error behavior %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("wrong description text %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
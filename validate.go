@@ -0,0 +1,252 @@
+package cleanenv
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// validate checks the field value against the validation tags.
+// Empty (zero) values not set by any source are not validated, use env-required to forbid them.
+// Values explicitly set to zero, e.g. by an environment variable, are validated as any other value.
+func (sm *structMeta) validate() error {
+	if !sm.set && sm.isFieldValueZero() {
+		return nil
+	}
+
+	if sm.min != nil {
+		if err := sm.checkBound(*sm.min, TagEnvMin); err != nil {
+			return err
+		}
+	}
+
+	if sm.max != nil {
+		if err := sm.checkBound(*sm.max, TagEnvMax); err != nil {
+			return err
+		}
+	}
+
+	if sm.length != nil {
+		if !hasLength(sm.fieldValue) {
+			return fmt.Errorf("tag %s is not supported for type %s", TagEnvLen, sm.fieldValue.Type())
+		}
+		n, err := strconv.Atoi(*sm.length)
+		if err != nil {
+			return fmt.Errorf("invalid %s value %q: %v", TagEnvLen, *sm.length, err)
+		}
+		if sm.fieldValue.Len() != n {
+			return fmt.Errorf("length must be %d", n)
+		}
+	}
+
+	if len(sm.oneOf) > 0 {
+		found := false
+		for _, option := range sm.oneOf {
+			expected := reflect.New(sm.fieldValue.Type()).Elem()
+			if err := parseValue(expected, option, sm.separator, sm.layout); err != nil {
+				return fmt.Errorf("invalid %s value %q: %v", TagEnvOneOf, option, err)
+			}
+			if reflect.DeepEqual(sm.fieldValue.Interface(), expected.Interface()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("value must be one of %q", strings.Join(sm.oneOf, DefaultSeparator))
+		}
+	}
+
+	if sm.pattern != nil {
+		if sm.fieldValue.Kind() != reflect.String {
+			return fmt.Errorf("tag %s is not supported for type %s", TagEnvPattern, sm.fieldValue.Type())
+		}
+		re, err := regexp.Compile(*sm.pattern)
+		if err != nil {
+			return fmt.Errorf("invalid %s value %q: %v", TagEnvPattern, *sm.pattern, err)
+		}
+		if !re.MatchString(sm.fieldValue.String()) {
+			return fmt.Errorf("value must match pattern %q", *sm.pattern)
+		}
+	}
+
+	return nil
+}
+
+// checkRequirements checks conditional requirements and exclusive groups.
+// Fields are referenced by name within the same structure.
+func checkRequirements(metas []structMeta) error {
+	type groupKey struct {
+		path, name string
+	}
+
+	var (
+		groups     = make(map[groupKey][]*structMeta)
+		groupOrder = make([]groupKey, 0)
+	)
+
+	for i := range metas {
+		meta := &metas[i]
+		fieldPath := meta.path + meta.fieldName
+
+		if meta.requiredIf != nil && meta.isFieldValueZero() {
+			kv := strings.SplitN(*meta.requiredIf, "=", 2)
+			if len(kv) != 2 {
+				return fmt.Errorf("field %q: invalid %s value %q", fieldPath, TagEnvRequiredIf, *meta.requiredIf)
+			}
+			ref := findSibling(metas, meta.path, kv[0])
+			if ref == nil {
+				return fmt.Errorf("field %q: field %q referenced by %s not found", fieldPath, kv[0], TagEnvRequiredIf)
+			}
+			expected := reflect.New(ref.fieldValue.Type()).Elem()
+			if err := parseValue(expected, kv[1], ref.separator, ref.layout); err != nil {
+				return fmt.Errorf("field %q: invalid %s value %q: %v", fieldPath, TagEnvRequiredIf, *meta.requiredIf, err)
+			}
+			if reflect.DeepEqual(ref.fieldValue.Interface(), expected.Interface()) {
+				return fmt.Errorf("field %q is required if %s but the value is not provided", fieldPath, *meta.requiredIf)
+			}
+		}
+
+		if len(meta.requiredWith) > 0 && meta.isFieldValueZero() {
+			for _, name := range meta.requiredWith {
+				ref := findSibling(metas, meta.path, name)
+				if ref == nil {
+					return fmt.Errorf("field %q: field %q referenced by %s not found", fieldPath, name, TagEnvRequiredWith)
+				}
+				if !ref.isFieldValueZero() {
+					return fmt.Errorf("field %q is required with %q but the value is not provided", fieldPath, name)
+				}
+			}
+		}
+
+		if meta.exclusiveGroup != "" {
+			key := groupKey{meta.path, meta.exclusiveGroup}
+			if _, ok := groups[key]; !ok {
+				groupOrder = append(groupOrder, key)
+			}
+			groups[key] = append(groups[key], meta)
+		}
+	}
+
+	for _, key := range groupOrder {
+		var (
+			names    = make([]string, 0, len(groups[key]))
+			set      = make([]string, 0)
+			required bool
+		)
+		for _, meta := range groups[key] {
+			names = append(names, fmt.Sprintf("%q", meta.path+meta.fieldName))
+			if !meta.isFieldValueZero() {
+				set = append(set, fmt.Sprintf("%q", meta.path+meta.fieldName))
+			}
+			required = required || meta.required
+		}
+
+		if len(set) > 1 {
+			return fmt.Errorf("fields %s of exclusive group %q can't be set together", strings.Join(set, ", "), key.name)
+		}
+		if required && len(set) == 0 {
+			return fmt.Errorf("one of fields %s of exclusive group %q is required but the value is not provided",
+				strings.Join(names, ", "), key.name,
+			)
+		}
+	}
+
+	return nil
+}
+
+// findSibling looks for a field by name in the structure with the given path
+func findSibling(metas []structMeta, path, name string) *structMeta {
+	for i := range metas {
+		if metas[i].path == path && metas[i].fieldName == name {
+			return &metas[i]
+		}
+	}
+	return nil
+}
+
+// checkBound compares the field value with the env-min or env-max bound.
+// Strings, slices and maps are compared by length, other types by value.
+func (sm *structMeta) checkBound(bound, tag string) error {
+	var (
+		cmp  int
+		what = "value"
+	)
+
+	if hasLength(sm.fieldValue) {
+		n, err := strconv.Atoi(bound)
+		if err != nil {
+			return fmt.Errorf("invalid %s value %q: %v", tag, bound, err)
+		}
+		cmp, what = compareInt(int64(sm.fieldValue.Len()), int64(n)), "length"
+	} else {
+		b := reflect.New(sm.fieldValue.Type()).Elem()
+		if err := parseValue(b, bound, sm.separator, sm.layout); err != nil {
+			return fmt.Errorf("invalid %s value %q: %v", tag, bound, err)
+		}
+
+		v := sm.fieldValue
+		switch {
+		case v.Type() == reflect.TypeOf(time.Time{}):
+			t, bt := v.Interface().(time.Time), b.Interface().(time.Time)
+			cmp = compareInt(t.UnixNano(), bt.UnixNano())
+		case v.Kind() >= reflect.Int && v.Kind() <= reflect.Int64:
+			cmp = compareInt(v.Int(), b.Int())
+		case v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uint64:
+			cmp = compareUint(v.Uint(), b.Uint())
+		case v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64:
+			cmp = compareFloat(v.Float(), b.Float())
+		default:
+			return fmt.Errorf("tag %s is not supported for type %s", tag, v.Type())
+		}
+	}
+
+	if tag == TagEnvMin && cmp < 0 {
+		return fmt.Errorf("%s must be at least %s", what, bound)
+	}
+	if tag == TagEnvMax && cmp > 0 {
+		return fmt.Errorf("%s must be at most %s", what, bound)
+	}
+	return nil
+}
+
+// hasLength determines if the value is validated by its length
+func hasLength(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return true
+	}
+	return false
+}
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
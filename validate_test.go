@@ -0,0 +1,521 @@
+package cleanenv
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestValidationTags(t *testing.T) {
+	type config struct {
+		Port    int           `env:"TEST_PORT" env-min:"1" env-max:"65535"`
+		Ratio   float64       `env:"TEST_RATIO" env-min:"0.5" env-max:"1"`
+		Workers uint          `env:"TEST_WORKERS" env-max:"8"`
+		Timeout time.Duration `env:"TEST_TIMEOUT" env-min:"1s" env-max:"1m"`
+		Level   string        `env:"TEST_LEVEL" env-oneof:"debug, info,warn"`
+		Name    string        `env:"TEST_NAME" env-min:"3" env-max:"5"`
+		Code    string        `env:"TEST_CODE" env-len:"2" env-pattern:"^[A-Z]+$"`
+		Hosts   []string      `env:"TEST_HOSTS" env-min:"2"`
+		Date    time.Time     `env:"TEST_DATE" env-layout:"2006-01-02" env-min:"2020-01-01"`
+		Threads int           `env:"TEST_THREADS" env-min:"1" env-default:"4"`
+	}
+
+	tests := []struct {
+		name          string
+		env           map[string]string
+		expectedError string
+	}{
+		{
+			name: "valid",
+			env: map[string]string{
+				"TEST_PORT":    "8080",
+				"TEST_RATIO":   "0.75",
+				"TEST_WORKERS": "8",
+				"TEST_TIMEOUT": "30s",
+				"TEST_LEVEL":   "info",
+				"TEST_NAME":    "test",
+				"TEST_CODE":    "EN",
+				"TEST_HOSTS":   "a,b",
+				"TEST_DATE":    "2021-06-01",
+			},
+		},
+		{
+			name: "empty values are not validated",
+			env:  nil,
+		},
+		{
+			name:          "explicit zero",
+			env:           map[string]string{"TEST_THREADS": "0"},
+			expectedError: `validating field "Threads" env "TEST_THREADS": value must be at least 1`,
+		},
+		{
+			name:          "explicit empty value",
+			env:           map[string]string{"TEST_LEVEL": ""},
+			expectedError: `validating field "Level" env "TEST_LEVEL": value must be one of "debug,info,warn"`,
+		},
+		{
+			name:          "int too large",
+			env:           map[string]string{"TEST_PORT": "70000"},
+			expectedError: `validating field "Port" env "TEST_PORT": value must be at most 65535`,
+		},
+		{
+			name:          "float too small",
+			env:           map[string]string{"TEST_RATIO": "0.1"},
+			expectedError: `validating field "Ratio" env "TEST_RATIO": value must be at least 0.5`,
+		},
+		{
+			name:          "uint too large",
+			env:           map[string]string{"TEST_WORKERS": "16"},
+			expectedError: `validating field "Workers" env "TEST_WORKERS": value must be at most 8`,
+		},
+		{
+			name:          "duration too small",
+			env:           map[string]string{"TEST_TIMEOUT": "10ms"},
+			expectedError: `validating field "Timeout" env "TEST_TIMEOUT": value must be at least 1s`,
+		},
+		{
+			name:          "not one of",
+			env:           map[string]string{"TEST_LEVEL": "trace"},
+			expectedError: `validating field "Level" env "TEST_LEVEL": value must be one of "debug,info,warn"`,
+		},
+		{
+			name:          "string too short",
+			env:           map[string]string{"TEST_NAME": "ab"},
+			expectedError: `validating field "Name" env "TEST_NAME": length must be at least 3`,
+		},
+		{
+			name:          "wrong length",
+			env:           map[string]string{"TEST_CODE": "ENG"},
+			expectedError: `validating field "Code" env "TEST_CODE": length must be 2`,
+		},
+		{
+			name:          "pattern mismatch",
+			env:           map[string]string{"TEST_CODE": "en"},
+			expectedError: `validating field "Code" env "TEST_CODE": value must match pattern "^[A-Z]+$"`,
+		},
+		{
+			name:          "slice too short",
+			env:           map[string]string{"TEST_HOSTS": "a"},
+			expectedError: `validating field "Hosts" env "TEST_HOSTS": length must be at least 2`,
+		},
+		{
+			name:          "time too early",
+			env:           map[string]string{"TEST_DATE": "2019-12-31"},
+			expectedError: `validating field "Date" env "TEST_DATE": value must be at least 2020-01-01`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for env, val := range tt.env {
+				os.Setenv(env, val)
+			}
+			defer os.Clearenv()
+
+			var cfg config
+			err := readEnvVars(&cfg, false)
+
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error but got nil")
+			}
+			if err.Error() != tt.expectedError {
+				t.Errorf("unexpected error message: got %q, want %q", err.Error(), tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidationTagsFile(t *testing.T) {
+	type config struct {
+		Workers int    `yaml:"workers" env-min:"1"`
+		Level   string `yaml:"level" env-oneof:"debug,info"`
+	}
+
+	tests := []struct {
+		name          string
+		data          string
+		expectedError string
+	}{
+		{
+			name: "keys are not set",
+			data: "{}\n",
+		},
+		{
+			name:          "explicit zero",
+			data:          "workers: 0\n",
+			expectedError: `validating field "Workers" env "": value must be at least 1`,
+		},
+		{
+			name:          "explicit empty value",
+			data:          "level: \"\"\n",
+			expectedError: `validating field "Level" env "": value must be one of "debug,info"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile, err := ioutil.TempFile(os.TempDir(), "*.yml")
+			if err != nil {
+				t.Fatal("cannot create temporary file:", err)
+			}
+			defer os.Remove(tmpFile.Name())
+
+			if _, err = tmpFile.Write([]byte(tt.data)); err != nil {
+				t.Fatal("failed to write to temporary file:", err)
+			}
+
+			var cfg config
+			err = ReadConfig(tmpFile.Name(), &cfg)
+
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.expectedError {
+				t.Errorf("unexpected error: got %v, want %q", err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidationTagsInvalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           interface{}
+		expectedError string
+	}{
+		{
+			name: "bad bound",
+			cfg: &struct {
+				Port int `env-default:"1" env-min:"one"`
+			}{},
+			expectedError: `validating field "Port" env "": invalid env-min value "one": strconv.ParseInt: parsing "one": invalid syntax`,
+		},
+		{
+			name: "bad pattern",
+			cfg: &struct {
+				Name string `env-default:"a" env-pattern:"("`
+			}{},
+			expectedError: "validating field \"Name\" env \"\": invalid env-pattern value \"(\": error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name: "unsupported type",
+			cfg: &struct {
+				Flag bool `env-default:"true" env-max:"false"`
+			}{},
+			expectedError: `validating field "Flag" env "": tag env-max is not supported for type bool`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := readEnvVars(tt.cfg, false)
+			if err == nil {
+				t.Fatalf("expected error but got nil")
+			}
+			if err.Error() != tt.expectedError {
+				t.Errorf("unexpected error message: got %q, want %q", err.Error(), tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestGetDescriptionValidation(t *testing.T) {
+	type config struct {
+		Level string `env:"LEVEL" env-description:"log level" env-default:"info" env-oneof:"debug,info"`
+		Port  int    `env:"PORT" env-description:"port" env-min:"1" env-max:"65535"`
+		Code  string `env:"CODE" env-description:"code" env-len:"2" env-pattern:"^[A-Z]+$"`
+	}
+
+	want := "Environment variables:" +
+		"\n  LEVEL string\n    \tlog level (default \"info\") (one of \"debug,info\")" +
+		"\n  PORT int\n    \tport (min 1) (max 65535)" +
+		"\n  CODE string\n    \tcode (length 2) (pattern \"^[A-Z]+$\")"
+
+	got, err := GetDescription(&config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("wrong description text %s, want %s", got, want)
+	}
+}
+
+type testValidatorDB struct {
+	Host string `env:"HOST"`
+	Port int    `env:"PORT"`
+}
+
+func (c *testValidatorDB) Validate() error {
+	if c.Host == "" && c.Port != 0 {
+		return errors.New("port is set without a host")
+	}
+	return nil
+}
+
+type testValidatorRoot struct {
+	Primary testValidatorDB `env-prefix:"TEST_PRIMARY_"`
+	Replica struct {
+		DB testValidatorDB `env-prefix:"DB_"`
+	} `env-prefix:"TEST_REPLICA_"`
+	Name string `env:"TEST_NAME"`
+
+	calls *[]string
+}
+
+func (c *testValidatorRoot) Validate() error {
+	*c.calls = append(*c.calls, "root")
+	if c.Name == "" {
+		return errors.New("name is empty")
+	}
+	return nil
+}
+
+func TestValidator(t *testing.T) {
+	tests := []struct {
+		name          string
+		env           map[string]string
+		expectedError string
+	}{
+		{
+			name: "valid",
+			env: map[string]string{
+				"TEST_PRIMARY_HOST": "localhost",
+				"TEST_PRIMARY_PORT": "5432",
+				"TEST_NAME":         "test",
+			},
+		},
+		{
+			name: "nested error",
+			env: map[string]string{
+				"TEST_PRIMARY_PORT": "5432",
+				"TEST_NAME":         "test",
+			},
+			expectedError: `validating "Primary": port is set without a host`,
+		},
+		{
+			name: "deep nested error before root",
+			env: map[string]string{
+				"TEST_REPLICA_DB_PORT": "5432",
+			},
+			expectedError: `validating "Replica.DB": port is set without a host`,
+		},
+		{
+			name:          "root error",
+			env:           nil,
+			expectedError: "name is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for env, val := range tt.env {
+				os.Setenv(env, val)
+			}
+			defer os.Clearenv()
+
+			cfg := testValidatorRoot{calls: &[]string{}}
+			err := readEnvVars(&cfg, false)
+
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if len(*cfg.calls) != 1 {
+					t.Errorf("root validator called %d times, want 1", len(*cfg.calls))
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error but got nil")
+			}
+			if err.Error() != tt.expectedError {
+				t.Errorf("unexpected error message: got %q, want %q", err.Error(), tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestConditionalRequirements(t *testing.T) {
+	type config struct {
+		TLS struct {
+			Enabled bool   `env:"TEST_TLS_ENABLED"`
+			Cert    string `env:"TEST_TLS_CERT" env-required-if:"Enabled=true"`
+			Key     string `env:"TEST_TLS_KEY" env-required-with:"Cert"`
+		}
+		Auth struct {
+			Token    string `env:"TEST_TOKEN" env-exclusive-group:"auth" env-required:"true"`
+			Username string `env:"TEST_USERNAME" env-exclusive-group:"auth" env-required:"true"`
+			Password string `env:"TEST_PASSWORD" env-required-with:"Username"`
+		}
+		Proxy    string `env:"TEST_PROXY" env-exclusive-group:"network"`
+		NoProxy  bool   `env:"TEST_NO_PROXY" env-exclusive-group:"network"`
+		Optional string `env:"TEST_OPTIONAL"`
+	}
+
+	tests := []struct {
+		name          string
+		env           map[string]string
+		expectedError string
+	}{
+		{
+			name: "valid",
+			env: map[string]string{
+				"TEST_TLS_ENABLED": "true",
+				"TEST_TLS_CERT":    "cert.pem",
+				"TEST_TLS_KEY":     "key.pem",
+				"TEST_TOKEN":       "token",
+			},
+		},
+		{
+			name: "condition not met",
+			env: map[string]string{
+				"TEST_TLS_ENABLED": "false",
+				"TEST_USERNAME":    "user",
+				"TEST_PASSWORD":    "pass",
+			},
+		},
+		{
+			name: "required if",
+			env: map[string]string{
+				"TEST_TLS_ENABLED": "true",
+				"TEST_TOKEN":       "token",
+			},
+			expectedError: `field "TLS.Cert" is required if Enabled=true but the value is not provided`,
+		},
+		{
+			name: "required with",
+			env: map[string]string{
+				"TEST_TLS_CERT": "cert.pem",
+				"TEST_TOKEN":    "token",
+			},
+			expectedError: `field "TLS.Key" is required with "Cert" but the value is not provided`,
+		},
+		{
+			name: "exclusive group conflict",
+			env: map[string]string{
+				"TEST_TOKEN":    "token",
+				"TEST_USERNAME": "user",
+				"TEST_PASSWORD": "pass",
+			},
+			expectedError: `fields "Auth.Token", "Auth.Username" of exclusive group "auth" can't be set together`,
+		},
+		{
+			name:          "exclusive group required",
+			env:           nil,
+			expectedError: `one of fields "Auth.Token", "Auth.Username" of exclusive group "auth" is required but the value is not provided`,
+		},
+		{
+			name: "optional exclusive group conflict",
+			env: map[string]string{
+				"TEST_TOKEN":    "token",
+				"TEST_PROXY":    "proxy:8080",
+				"TEST_NO_PROXY": "true",
+			},
+			expectedError: `fields "Proxy", "NoProxy" of exclusive group "network" can't be set together`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for env, val := range tt.env {
+				os.Setenv(env, val)
+			}
+			defer os.Clearenv()
+
+			var cfg config
+			err := readEnvVars(&cfg, false)
+
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error but got nil")
+			}
+			if err.Error() != tt.expectedError {
+				t.Errorf("unexpected error message: got %q, want %q", err.Error(), tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestConditionalRequirementsInvalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           interface{}
+		expectedError string
+	}{
+		{
+			name: "no condition value",
+			cfg: &struct {
+				One string `env-required-if:"Two"`
+				Two string
+			}{},
+			expectedError: `field "One": invalid env-required-if value "Two"`,
+		},
+		{
+			name: "unknown field",
+			cfg: &struct {
+				One string `env-required-if:"Three=true"`
+			}{},
+			expectedError: `field "One": field "Three" referenced by env-required-if not found`,
+		},
+		{
+			name: "unknown required with field",
+			cfg: &struct {
+				One string `env-required-with:"Three"`
+			}{},
+			expectedError: `field "One": field "Three" referenced by env-required-with not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := readEnvVars(tt.cfg, false)
+			if err == nil {
+				t.Fatalf("expected error but got nil")
+			}
+			if err.Error() != tt.expectedError {
+				t.Errorf("unexpected error message: got %q, want %q", err.Error(), tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestGetDescriptionRequirements(t *testing.T) {
+	type config struct {
+		Enabled  bool   `env:"TLS_ENABLED" env-description:"enable TLS"`
+		Cert     string `env:"TLS_CERT" env-description:"certificate" env-required-if:"Enabled=true"`
+		Key      string `env:"TLS_KEY" env-description:"key" env-required-with:"Cert"`
+		Token    string `env:"TOKEN" env-description:"token" env-exclusive-group:"auth"`
+		Username string `env:"USERNAME" env-description:"username" env-exclusive-group:"auth"`
+	}
+
+	want := "Environment variables:" +
+		"\n  TLS_ENABLED bool\n    \tenable TLS" +
+		"\n  TLS_CERT string\n    \tcertificate (required if Enabled=true)" +
+		"\n  TLS_KEY string\n    \tkey (required with Cert)" +
+		"\n  TOKEN string\n    \ttoken (exclusive group \"auth\")" +
+		"\n  USERNAME string\n    \tusername (exclusive group \"auth\")"
+
+	got, err := GetDescription(&config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("wrong description text %s, want %s", got, want)
+	}
+}
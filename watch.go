@@ -0,0 +1,218 @@
+package cleanenv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+)
+
+// watchInterval is the configuration file polling interval
+var watchInterval = time.Second
+
+// watchStarted is called by Watch after the initial file state is read, it is used by tests
+var watchStarted = func() {}
+
+// WithSignals sets the signals ReloadOnSignal reloads the configuration on. By default, SIGHUP is used.
+// The option is ignored by other functions.
+func WithSignals(signals ...os.Signal) Option {
+	return func(o *options) {
+		o.signals = signals
+	}
+}
+
+// ReloadFunc is called after the configuration is reloaded.
+// Arguments old and new are pointers to the configuration structure before and after the reload.
+// If the reload failed, err is not nil, new is nil and the configuration keeps its previous values.
+type ReloadFunc func(old, new interface{}, err error)
+
+// Watch polls the configuration file and reloads the configuration when the file changes.
+//
+// The configuration is read from the file and environment variables into a new structure the same way as ReadConfig does.
+// If the new configuration is valid, only updatable fields (marked with `env-upd` tag) are copied into cfg
+// and onChange is called if any of them has changed. Otherwise, cfg keeps its previous values and onChange receives the error.
+//
+// The options are applied to every reload, so they must match the options the configuration was read with,
+// e.g. WithPrefix or WithAutoEnv.
+//
+// Watch blocks until the context is done and returns the context error.
+// The configuration structure is modified by Watch, so it must not be accessed by other goroutines without synchronization.
+//
+// Example:
+//
+//	go cleanenv.Watch(ctx, "config.yml", &cfg, func(old, new interface{}, err error) {
+//		if err != nil {
+//			log.Printf("config reload failed: %v", err)
+//		}
+//	})
+func Watch(ctx context.Context, path string, cfg interface{}, onChange ReloadFunc, opts ...Option) error {
+	if _, err := cfgStruct(cfg); err != nil {
+		return err
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	watchStarted()
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		newStat, err := os.Stat(path)
+		if err != nil {
+			// report only the first error until the file is back
+			if stat != nil && onChange != nil {
+				onChange(cfg, nil, err)
+			}
+			stat = nil
+			continue
+		}
+
+		if stat != nil && stat.ModTime().Equal(newStat.ModTime()) && stat.Size() == newStat.Size() {
+			continue
+		}
+		stat = newStat
+
+		old, err := reload(path, cfg, opts)
+		if onChange == nil {
+			continue
+		}
+		if err != nil {
+			onChange(cfg, nil, err)
+		} else if !reflect.DeepEqual(old, cfg) {
+			onChange(old, cfg, nil)
+		}
+	}
+}
+
+// ReloadOnSignal reloads the configuration each time the process receives one of the signals
+// (SIGHUP by default, use WithSignals option to change them).
+//
+// The configuration is read from the file and environment variables into a new structure the same way as ReadConfig does,
+// or as ReadEnv does if the path is empty. If the new configuration is valid, only updatable fields (marked with `env-upd` tag)
+// are copied into cfg. Otherwise, cfg keeps its previous values. The result of every reload is reported to onReload.
+//
+// The options are applied to every reload, so they must match the options the configuration was read with.
+//
+// ReloadOnSignal blocks until the context is done and returns the context error.
+// The configuration structure is modified by ReloadOnSignal, so it must not be accessed by other goroutines without synchronization.
+//
+// Example:
+//
+//	go cleanenv.ReloadOnSignal(ctx, "config.yml", &cfg, func(old, new interface{}, err error) {
+//		if err != nil {
+//			log.Printf("config reload failed: %v", err)
+//			return
+//		}
+//		log.Print("config reloaded")
+//	}, cleanenv.WithSignals(syscall.SIGHUP, syscall.SIGUSR1))
+func ReloadOnSignal(ctx context.Context, path string, cfg interface{}, onReload ReloadFunc, opts ...Option) error {
+	if _, err := cfgStruct(cfg); err != nil {
+		return err
+	}
+
+	signals := newOptions(opts).signals
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+	defer signal.Stop(ch)
+
+	return reloadOn(ctx, ch, path, cfg, onReload, opts)
+}
+
+// reloadOn reloads the configuration each time a signal is received from the channel
+func reloadOn(ctx context.Context, ch <-chan os.Signal, path string, cfg interface{}, onReload ReloadFunc, opts []Option) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ch:
+		}
+
+		old, err := reload(path, cfg, opts)
+		if onReload == nil {
+			continue
+		}
+		if err != nil {
+			onReload(cfg, nil, err)
+		} else {
+			onReload(old, cfg, nil)
+		}
+	}
+}
+
+// reload reads the configuration into a new structure and copies updatable fields into cfg.
+// If the path is empty, only environment variables are read.
+// It returns a copy of cfg made before the update and calls functions registered by OnChange for the changed fields.
+// If the new configuration can't be read, cfg is left unchanged.
+func reload(path string, cfg interface{}, opts []Option) (interface{}, error) {
+	s, err := cfgStruct(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	fresh := reflect.New(s.Type())
+	if path != "" {
+		err = ReadConfig(path, fresh.Interface(), opts...)
+	} else {
+		err = ReadEnv(fresh.Interface(), opts...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	old := reflect.New(s.Type())
+	old.Elem().Set(s)
+
+	if err = copyUpdatable(cfg, fresh.Interface()); err != nil {
+		return nil, err
+	}
+
+	notifyChanges(cfg, old.Interface())
+
+	return old.Interface(), nil
+}
+
+// copyUpdatable copies updatable fields from src to dst structure of the same type
+func copyUpdatable(dst, src interface{}) error {
+	dstMeta, err := readStructMetadata(dst)
+	if err != nil {
+		return err
+	}
+
+	srcMeta, err := readStructMetadata(src)
+	if err != nil {
+		return err
+	}
+
+	for i := range dstMeta {
+		if dstMeta[i].updatable {
+			dstMeta[i].fieldValue.Set(srcMeta[i].fieldValue)
+		}
+	}
+
+	return nil
+}
+
+// cfgStruct returns the structure value the configuration pointer refers to
+func cfgStruct(cfg interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("wrong type %v, pointer to a structure expected", v.Kind())
+	}
+	return v.Elem(), nil
+}
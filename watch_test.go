@@ -0,0 +1,201 @@
+package cleanenv
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type testWatchConfig struct {
+	Host  string `yaml:"host" env:"TEST_WATCH_HOST"`
+	Level string `yaml:"level" env:"TEST_WATCH_LEVEL" env-upd:"" env-oneof:"debug,info"`
+	Limit int    `yaml:"limit" env-upd:"" env-default:"10"`
+}
+
+type reloadResult struct {
+	old, new interface{}
+	err      error
+}
+
+func TestWatch(t *testing.T) {
+	defer func(interval time.Duration, started func()) {
+		watchInterval, watchStarted = interval, started
+	}(watchInterval, watchStarted)
+	watchInterval = 10 * time.Millisecond
+
+	started := make(chan struct{})
+	watchStarted = func() { close(started) }
+
+	tmpFile, err := ioutil.TempFile(os.TempDir(), "*.yml")
+	if err != nil {
+		t.Fatal("cannot create temporary file:", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	writeFile := func(text string, modTime time.Time) {
+		if err := ioutil.WriteFile(tmpFile.Name(), []byte(text), 0o600); err != nil {
+			t.Fatal("failed to write to temporary file:", err)
+		}
+		if err := os.Chtimes(tmpFile.Name(), modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+	writeFile("host: a\nlevel: info\nlimit: 5\n", now)
+
+	var cfg testWatchConfig
+	if err = ReadConfig(tmpFile.Name(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	results := make(chan reloadResult)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- Watch(ctx, tmpFile.Name(), &cfg, func(old, new interface{}, err error) {
+			results <- reloadResult{old, new, err}
+		})
+	}()
+
+	wait := func(t *testing.T) reloadResult {
+		select {
+		case res := <-results:
+			return res
+		case <-time.After(time.Second):
+			t.Fatal("no reload")
+		}
+		return reloadResult{}
+	}
+
+	// changes are detected only after the watcher reads the initial file state
+	<-started
+
+	t.Run("updatable fields", func(t *testing.T) {
+		writeFile("host: b\nlevel: debug\n", now.Add(time.Second))
+
+		res := wait(t)
+		if res.err != nil {
+			t.Fatal(res.err)
+		}
+		wantOld := &testWatchConfig{Host: "a", Level: "info", Limit: 5}
+		wantNew := &testWatchConfig{Host: "a", Level: "debug", Limit: 10}
+		if !reflect.DeepEqual(res.old, wantOld) {
+			t.Errorf("wrong old data %v, want %v", res.old, wantOld)
+		}
+		if !reflect.DeepEqual(res.new, wantNew) {
+			t.Errorf("wrong new data %v, want %v", res.new, wantNew)
+		}
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		writeFile("host: b\nlevel: trace\n", now.Add(2*time.Second))
+
+		res := wait(t)
+		if res.err == nil {
+			t.Fatal("expected error but got nil")
+		}
+		want := testWatchConfig{Host: "a", Level: "debug", Limit: 10}
+		if !reflect.DeepEqual(cfg, want) {
+			t.Errorf("wrong data %v, want %v", cfg, want)
+		}
+	})
+
+	cancel()
+	if err = <-done; err != context.Canceled {
+		t.Errorf("wrong error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestWatchErrors(t *testing.T) {
+	var cfg testWatchConfig
+
+	if err := Watch(context.Background(), "invalid file path", &cfg, nil); err == nil {
+		t.Error("expected error for invalid file path")
+	}
+
+	if err := Watch(context.Background(), "invalid file path", cfg, nil); err == nil {
+		t.Error("expected error for non-pointer config")
+	}
+}
+
+func TestReloadOn(t *testing.T) {
+	tmpFile, err := ioutil.TempFile(os.TempDir(), "*.yml")
+	if err != nil {
+		t.Fatal("cannot create temporary file:", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err = tmpFile.Write([]byte("host: a\nlevel: info\n")); err != nil {
+		t.Fatal("failed to write to temporary file:", err)
+	}
+
+	var cfg testWatchConfig
+	if err = ReadConfig(tmpFile.Name(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	signals := make(chan os.Signal)
+	results := make(chan reloadResult)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- reloadOn(ctx, signals, tmpFile.Name(), &cfg, func(old, new interface{}, err error) {
+			results <- reloadResult{old, new, err}
+		}, nil)
+	}()
+
+	defer os.Clearenv()
+
+	t.Run("success", func(t *testing.T) {
+		os.Setenv("TEST_WATCH_HOST", "b")
+		os.Setenv("TEST_WATCH_LEVEL", "debug")
+		signals <- syscall.SIGHUP
+
+		res := <-results
+		if res.err != nil {
+			t.Fatal(res.err)
+		}
+		want := &testWatchConfig{Host: "a", Level: "debug", Limit: 10}
+		if !reflect.DeepEqual(res.new, want) {
+			t.Errorf("wrong new data %v, want %v", res.new, want)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		os.Setenv("TEST_WATCH_LEVEL", "trace")
+		signals <- syscall.SIGHUP
+
+		res := <-results
+		if res.err == nil {
+			t.Fatal("expected error but got nil")
+		}
+		want := testWatchConfig{Host: "a", Level: "debug", Limit: 10}
+		if !reflect.DeepEqual(cfg, want) {
+			t.Errorf("wrong data %v, want %v", cfg, want)
+		}
+	})
+
+	cancel()
+	if err = <-done; err != context.Canceled {
+		t.Errorf("wrong error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestReloadOnSignalErrors(t *testing.T) {
+	var cfg testWatchConfig
+
+	if err := ReloadOnSignal(context.Background(), "", cfg, nil); err == nil {
+		t.Error("expected error for non-pointer config")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := ReloadOnSignal(ctx, "", &cfg, nil); err != context.Canceled {
+		t.Errorf("wrong error %v, want %v", err, context.Canceled)
+	}
+}